@@ -0,0 +1,341 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"test_task/internal/data"
+	"test_task/internal/validator"
+)
+
+// @Summary Add a new album
+// @Description Adds a new album of a group
+// @Tags Albums
+// @Accept json
+// @Produce json
+// @Param album body object{title=string,group=string,release_date=string} true "Album title, group and optional release date (YYYY-MM-DD)"
+// @Success 201 {object} envelope{album=data.Album} "Album added successfully"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 422 {object} map[string]string "Validation errors"
+// @Failure 500 {object} map[string]string "the server encountered a problem and could not process your request"
+// @Router /v1/albums [post]
+func (app *application) addAlbumHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Title       string     `json:"title"`
+		Group       string     `json:"group"`
+		ReleaseDate *data.Date `json:"release_date"`
+	}
+
+	err := app.readJSON(w, r, &req)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		app.logger.Warn("bad request", slog.Any("error", err))
+		return
+	}
+
+	log := app.logger.With(
+		slog.String("group", req.Group),
+		slog.String("album", req.Title))
+
+	log.Info("attempting to add a new album")
+
+	album := &data.Album{
+		Title:       req.Title,
+		Group:       data.NormaliseGroupName(req.Group),
+		ReleaseDate: req.ReleaseDate,
+	}
+
+	v := validator.New()
+
+	if data.ValidateAlbum(v, album); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		app.logger.Warn("validation has not passed")
+		return
+	}
+
+	err = app.models.Albums.Insert(album)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrDuplicateAlbum):
+			v.AddError("title", "an album with this title already exists for the group")
+			app.failedValidationResponse(w, r, v.Errors)
+			app.logger.Warn("album is already in database", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to insert album", slog.Any("error", err))
+		}
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("Location", fmt.Sprintf("/v1/albums/%d", album.ID))
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"album": album}, headers)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to add album", slog.Any("error", err))
+		return
+	}
+
+	log.Info("album added successfully")
+}
+
+// @Summary Get an album by ID
+// @Description Retrieves an album by its ID
+// @Tags Albums
+// @Accept json
+// @Produce json
+// @Param id path int true "Album ID"
+// @Success 200 {object} envelope{album=data.Album} "Successfully retrieved album"
+// @Failure 404 {object} map[string]string "The requested resource could not be found"
+// @Failure 500 {object} map[string]string "The server encountered a problem and could not process your request"
+// @Router /v1/albums/{id} [get]
+func (app *application) showAlbumHandler(w http.ResponseWriter, r *http.Request) {
+	album, ok := app.getAlbum(w, r)
+	if !ok {
+		return
+	}
+
+	err := app.writeJSON(w, http.StatusOK, envelope{"album": album}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to get album", slog.Any("error", err))
+	}
+}
+
+// @Summary List albums
+// @Description Lists albums with optional filters and pagination
+// @Tags Albums
+// @Accept json
+// @Produce json
+// @Param title query string false "Album title"
+// @Param group query string false "Group name"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Number of items per page" default(5)
+// @Param sort query string false "Sort by field" default(id) Enum(id,title,group,release_date,-id,-title,-group,-release_date)
+// @Success 200 {object} envelope{albums=[]data.Album,metadata=data.Metadata} "List of albums"
+// @Failure 422 {object} map[string]string "Validation errors"
+// @Failure 500 {object} map[string]string "the server encountered a problem and could not process your request"
+// @Router /v1/albums [get]
+func (app *application) listAlbumsHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Title string
+		Group string
+		data.Filters
+	}
+
+	v := validator.New()
+	qs := r.URL.Query()
+
+	req.Title = app.readString(qs, "title", "")
+	req.Group = app.readString(qs, "group", "")
+
+	req.Filters.Page = app.readInt(qs, "page", 1, v)
+	req.Filters.PageSize = app.readInt(qs, "page_size", 5, v)
+	req.Filters.Sort = app.readString(qs, "sort", "id")
+	req.Filters.SortSafelist = []string{"id", "title", "group", "release_date", "-id", "-title", "-group", "-release_date"}
+
+	if data.ValidateFilters(v, req.Filters); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		app.logger.Warn("validation has not passed")
+		return
+	}
+
+	albums, metadata, err := app.models.Albums.GetAll(req.Title, req.Group, req.Filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("error getting albums", slog.Any("error", err))
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"albums": albums, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("error getting albums", slog.Any("error", err))
+	}
+}
+
+// @Summary List album tracks
+// @Description Lists the songs of an album in track order
+// @Tags Albums
+// @Accept json
+// @Produce json
+// @Param id path int true "Album ID"
+// @Success 200 {object} envelope{album=data.Album,tracks=[]data.Song} "Album tracks"
+// @Failure 404 {object} map[string]string "Album not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/albums/{id}/tracks [get]
+func (app *application) listAlbumTracksHandler(w http.ResponseWriter, r *http.Request) {
+	album, ok := app.getAlbum(w, r)
+	if !ok {
+		return
+	}
+
+	tracks, err := app.models.Albums.GetTracks(album.ID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("error getting album tracks", slog.Any("error", err))
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"album": album, "tracks": tracks}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("error getting album tracks", slog.Any("error", err))
+	}
+}
+
+// @Summary Set an album track
+// @Description Puts a song of the album's group on the album under the given track number
+// @Tags Albums
+// @Accept json
+// @Produce json
+// @Param id path int true "Album ID"
+// @Param number path int true "Track number"
+// @Param track body object{song_id=int} true "Song ID"
+// @Success 200 {object} envelope{song=data.Song} "Track set successfully"
+// @Header 200 {string} ETag "Version of the song, for If-Match"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Album or song not found"
+// @Failure 409 {object} map[string]string "Track number is already taken"
+// @Failure 422 {object} map[string]string "Validation errors"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/albums/{id}/tracks/{number} [put]
+func (app *application) setAlbumTrackHandler(w http.ResponseWriter, r *http.Request) {
+	album, ok := app.getAlbum(w, r)
+	if !ok {
+		return
+	}
+
+	number, err := app.readIntParam(r, "number")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var req struct {
+		SongID int64 `json:"song_id"`
+	}
+
+	err = app.readJSON(w, r, &req)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		app.logger.Warn("bad request", slog.Any("error", err))
+		return
+	}
+
+	log := app.logger.With(
+		slog.String("album", strconv.FormatInt(album.ID, 10)),
+		slog.Int64("track", number))
+
+	v := validator.New()
+
+	data.ValidateTrackNumber(v, int(number))
+
+	song, err := app.models.Songs.Get(req.SongID)
+	switch {
+	case errors.Is(err, data.ErrNoRecordFound):
+		v.AddError("song_id", "song does not exist")
+	case err != nil:
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to get song", slog.Any("error", err))
+		return
+	default:
+		v.Check(song.GroupID == album.GroupID, "song_id", "must be a song of the album's group")
+	}
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		app.logger.Warn("validation has not passed")
+		return
+	}
+
+	err = app.models.Albums.As(app.actor(r)).SetTrack(album.ID, song.ID, int(number))
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+		case errors.Is(err, data.ErrTrackTaken):
+			app.conflictResponse(w, r, "this track number is already taken on the album")
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		app.logger.Warn("failed to set album track", slog.Any("error", err))
+		return
+	}
+
+	app.writeSong(w, r, song.ID)
+
+	log.Info("album track set successfully")
+}
+
+// @Summary Remove an album track
+// @Description Takes the song with the given track number off the album
+// @Tags Albums
+// @Accept json
+// @Produce json
+// @Param id path int true "Album ID"
+// @Param number path int true "Track number"
+// @Success 200 {object} map[string]string "Track successfully removed"
+// @Failure 404 {object} map[string]string "Album or track not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/albums/{id}/tracks/{number} [delete]
+func (app *application) removeAlbumTrackHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	number, err := app.readIntParam(r, "number")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	err = app.models.Albums.As(app.actor(r)).RemoveTrack(id, int(number))
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("album track not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("error removing album track", slog.Any("error", err))
+		}
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "track successfully removed"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("error removing album track", slog.Any("error", err))
+	}
+}
+
+// getAlbum loads the album named by the id URL parameter, writing the error
+// response itself when that fails.
+func (app *application) getAlbum(w http.ResponseWriter, r *http.Request) (*data.Album, bool) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return nil, false
+	}
+
+	album, err := app.models.Albums.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("album not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to get album", slog.Any("error", err))
+		}
+		return nil, false
+	}
+
+	return album, true
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"test_task/internal/data"
+	"test_task/internal/validator"
+)
+
+// @Summary Song change history
+// @Description Lists the recorded changes of a song, newest first, including those made after it was deleted
+// @Tags Audit
+// @Accept json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Number of entries per page" default(20)
+// @Success 200 {object} envelope{history=[]data.AuditEntry,metadata=data.Metadata} "Song history"
+// @Failure 404 {object} map[string]string "Song not found"
+// @Failure 422 {object} map[string]string "Validation errors"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/song/{id}/history [get]
+func (app *application) songHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	v := validator.New()
+	qs := r.URL.Query()
+
+	filter := data.AuditFilter{SongID: id}
+	filter.Page = app.readInt(qs, "page", 1, v)
+	filter.PageSize = app.readInt(qs, "page_size", 20, v)
+
+	if data.ValidateAuditFilter(v, filter); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		app.logger.Warn("validation has not passed")
+		return
+	}
+
+	entries, metadata, err := app.models.Audit.GetAll(filter)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to get song history", slog.Any("error", err))
+		return
+	}
+
+	// Songs stored before the audit log existed have no entries yet; only a
+	// song that is neither stored nor ever recorded is missing.
+	if len(entries) == 0 && filter.Page == 1 {
+		exists, err := app.models.Songs.Exists(id)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to get song", slog.Any("error", err))
+			return
+		}
+
+		if !exists {
+			app.notFoundResponse(w, r)
+			return
+		}
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"history": entries, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to get song history", slog.Any("error", err))
+	}
+}
+
+// @Summary List audit log entries
+// @Description Lists recorded song changes, newest first, with optional filters
+// @Tags Audit
+// @Accept json
+// @Produce json
+// @Param song_id query int false "Song ID"
+// @Param actor query string false "Who made the change"
+// @Param action query string false "Kind of change" Enums(insert,update,delete,restore,purge)
+// @Param request_id query string false "Request that made the change"
+// @Param from query string false "Changed on or after (YYYY-MM-DD)"
+// @Param to query string false "Changed on or before (YYYY-MM-DD)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Number of entries per page" default(20)
+// @Success 200 {object} envelope{entries=[]data.AuditEntry,metadata=data.Metadata} "Audit log entries"
+// @Failure 422 {object} map[string]string "Validation errors"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/audit [get]
+func (app *application) listAuditHandler(w http.ResponseWriter, r *http.Request) {
+	v := validator.New()
+	qs := r.URL.Query()
+
+	var filter data.AuditFilter
+
+	filter.SongID = int64(app.readInt(qs, "song_id", 0, v))
+	filter.Actor = app.readString(qs, "actor", "")
+	filter.Action = app.readString(qs, "action", "")
+	filter.RequestID = app.readString(qs, "request_id", "")
+	filter.From = app.readDate(qs, "from", v)
+	filter.To = app.readDate(qs, "to", v)
+	filter.Page = app.readInt(qs, "page", 1, v)
+	filter.PageSize = app.readInt(qs, "page_size", 20, v)
+
+	if data.ValidateAuditFilter(v, filter); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		app.logger.Warn("validation has not passed")
+		return
+	}
+
+	entries, metadata, err := app.models.Audit.GetAll(filter)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to get audit log", slog.Any("error", err))
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"entries": entries, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to get audit log", slog.Any("error", err))
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"test_task/internal/data"
+	"test_task/internal/validator"
+)
+
+// @Summary Set song credits
+// @Description Replaces the groups credited on a song besides its primary group: featured artists, producers and composers
+// @Tags Songs
+// @Accept json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param credits body object{credits=[]data.Credit} true "Credited groups (role: feat, producer or composer)"
+// @Success 200 {object} envelope{song=data.Song} "Song with its credits"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Song not found"
+// @Failure 422 {object} map[string]string "Validation errors or duplicate song"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/song/{id}/credits [put]
+func (app *application) setSongCreditsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	log := app.logger.With(
+		slog.String("song", strconv.FormatInt(id, 10)))
+
+	log.Info("attempting to set song credits")
+
+	song, err := app.models.Songs.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("song not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to get song", slog.Any("error", err))
+		}
+		return
+	}
+
+	var req struct {
+		Credits []data.Credit `json:"credits"`
+	}
+
+	err = app.readJSON(w, r, &req)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		app.logger.Warn("bad request", slog.Any("error", err))
+		return
+	}
+
+	v := validator.New()
+
+	if data.ValidateCredits(v, song.Group, req.Credits); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		app.logger.Warn("validation has not passed")
+		return
+	}
+
+	err = app.models.Songs.As(app.actor(r)).SetCredits(id, req.Credits)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("song not found", slog.Any("error", err))
+		case errors.Is(err, data.ErrAlreadyExists):
+			v.AddError("credits", "a song of this group with the same name and featured artists already exists")
+			app.failedValidationResponse(w, r, v.Errors)
+			app.logger.Warn("song is already in database", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to set song credits", slog.Any("error", err))
+		}
+		return
+	}
+
+	app.writeSong(w, r, id)
+
+	log.Info("song credits set successfully")
+}
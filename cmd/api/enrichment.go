@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"test_task/internal/data"
+	"test_task/internal/provider"
+	"time"
+)
+
+const (
+	enrichmentTimeout    = time.Minute
+	refreshTimeout       = 30 * time.Second
+	refreshBatchTimeout  = 5 * time.Minute
+	enrichmentRetryBase  = 30 * time.Second
+	enrichmentRetryLimit = time.Hour
+)
+
+// Statuses of the songs of a batch refresh.
+const (
+	refreshDone         = "refreshed"
+	refreshFailed       = "failed"
+	refreshNotProcessed = "not_processed"
+)
+
+var (
+	enrichmentActor = data.Actor{Name: "system:enrichment"}
+	resyncActor     = data.Actor{Name: "system:resync"}
+	purgeActor      = data.Actor{Name: "system:purge"}
+)
+
+// startEnrichmentWorkers runs the configured number of workers that lease
+// enrichment jobs from the database until ctx is cancelled.
+func (app *application) startEnrichmentWorkers(ctx context.Context) {
+	for i := 0; i < app.config.enrichment.workers; i++ {
+		app.wg.Add(1)
+
+		go func() {
+			defer app.wg.Done()
+
+			app.runEnrichmentWorker(ctx)
+		}()
+	}
+}
+
+// wakeEnrichmentWorkers nudges an idle worker so a freshly queued job doesn't
+// wait for the next poll.
+func (app *application) wakeEnrichmentWorkers() {
+	select {
+	case app.enrichmentWake <- struct{}{}:
+	default:
+	}
+}
+
+func (app *application) runEnrichmentWorker(ctx context.Context) {
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		jobs, err := app.models.EnrichmentJobs.Lease(app.config.enrichment.lease, 1)
+		if err != nil {
+			app.logger.Error("failed to lease enrichment jobs", slog.Any("error", err))
+		}
+
+		for _, job := range jobs {
+			app.processEnrichmentJob(ctx, job)
+		}
+
+		if len(jobs) > 0 {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-app.enrichmentWake:
+		case <-time.After(app.config.enrichment.poll):
+		}
+	}
+}
+
+// processEnrichmentJob fetches the details of the job's song and stores them.
+// Transient failures are retried with backoff until the attempts run out,
+// after which the song is marked as failed with the reason. A job cut short
+// by ctx is released right away for another worker or replica.
+func (app *application) processEnrichmentJob(ctx context.Context, job *data.EnrichmentJob) {
+	log := app.logger.With(
+		slog.Int64("job", job.ID),
+		slog.Int64("id", job.SongID),
+		slog.Int("attempt", job.Attempts))
+
+	song, err := app.models.Songs.Get(job.SongID)
+	if err != nil {
+		if errors.Is(err, data.ErrNoRecordFound) {
+			err = app.models.EnrichmentJobs.Fail(job.ID, "song no longer exists")
+			if err != nil {
+				log.Error("failed to mark enrichment job as failed", slog.Any("error", err))
+			}
+			return
+		}
+		log.Error("failed to get song for enrichment", slog.Any("error", err))
+		return
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, enrichmentTimeout)
+	defer cancel()
+
+	detail, err := app.fetchSongDetail(fetchCtx, song.Song, song.Group)
+	if err != nil {
+		if ctx.Err() != nil {
+			err = app.models.EnrichmentJobs.Release(job.ID)
+			if err != nil {
+				log.Error("failed to release enrichment job", slog.Any("error", err))
+			}
+			log.Info("song enrichment interrupted")
+			return
+		}
+
+		if job.Attempts < app.config.enrichment.maxAttempts && !errors.Is(err, provider.ErrNotFound) {
+			err = app.models.EnrichmentJobs.Retry(job.ID, err.Error(), enrichmentBackoff(job.Attempts))
+			if err != nil {
+				log.Error("failed to reschedule enrichment job", slog.Any("error", err))
+				return
+			}
+			log.Warn("song enrichment failed, will retry")
+			return
+		}
+
+		reason := err.Error()
+
+		err = app.models.Songs.As(enrichmentActor).SetEnrichmentFailed(song.ID, reason)
+		if err != nil {
+			log.Error("failed to mark song enrichment as failed", slog.Any("error", err))
+		}
+
+		err = app.models.EnrichmentJobs.Fail(job.ID, reason)
+		if err != nil {
+			log.Error("failed to mark enrichment job as failed", slog.Any("error", err))
+		}
+
+		log.Warn("song enrichment failed", slog.String("reason", reason))
+		return
+	}
+
+	err = app.models.Songs.As(enrichmentActor).SetDetails(song.ID, detail)
+	if err != nil {
+		log.Error("failed to store song details", slog.Any("error", err))
+		return
+	}
+
+	err = app.models.EnrichmentJobs.Complete(job.ID)
+	if err != nil {
+		log.Error("failed to complete enrichment job", slog.Any("error", err))
+		return
+	}
+
+	log.Info("song enriched successfully")
+}
+
+func enrichmentBackoff(attempt int) time.Duration {
+	d := enrichmentRetryBase << (attempt - 1)
+	if d <= 0 || d > enrichmentRetryLimit {
+		return enrichmentRetryLimit
+	}
+
+	return d
+}
+
+// fetchError wraps the error of a details provider, so that callers can
+// tell it apart from a failure to store the fetched details.
+type fetchError struct {
+	err error
+}
+
+func (e *fetchError) Error() string {
+	return e.err.Error()
+}
+
+func (e *fetchError) Unwrap() error {
+	return e.err
+}
+
+// refreshSong re-fetches the details of song and returns what differs from
+// the stored ones. When apply is set the fresh details are saved on behalf of
+// actor, even if nothing changed, and song is updated in place. A failed
+// fetch is returned as a *fetchError.
+func (app *application) refreshSong(ctx context.Context, song *data.Song, apply bool, actor data.Actor) ([]data.DetailChange, error) {
+	detail, err := app.fetchSongDetail(provider.BypassCache(ctx), song.Song, song.Group)
+	if err != nil {
+		return nil, &fetchError{err: err}
+	}
+
+	// Lyrics edited by users are kept by SetDetails, so they are not a change.
+	if song.LyricsEditedAt != nil {
+		detail.Text = song.Text
+	}
+
+	changes := data.DiffDetails(song, detail)
+
+	if !apply {
+		return changes, nil
+	}
+
+	// Unchanged details are not written back, so the song gets no new
+	// lyrics revision; only where they came from is updated.
+	if len(changes) == 0 {
+		err = app.models.Songs.As(actor).SetDetailsChecked(song.ID, detail)
+		if err != nil {
+			return nil, err
+		}
+
+		song.Sources = detail.Sources
+		return changes, nil
+	}
+
+	link := song.LinkAfter(detail.Link)
+
+	err = app.models.Songs.As(actor).SetDetails(song.ID, detail)
+	if err != nil {
+		return nil, err
+	}
+
+	song.Release = detail.Release
+	song.ReleaseDate = data.ParseReleaseDate(detail.Release)
+	song.Text = detail.Text
+	song.Link = link
+	song.Sources = detail.Sources
+	song.EnrichmentStatus = data.EnrichmentDone
+	song.EnrichmentError = ""
+
+	return changes, nil
+}
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
+	"test_task/internal/provider"
 )
 
 func (app *application) logError(r *http.Request, err error) {
@@ -43,6 +45,43 @@ func (app *application) editConflictResponse(w http.ResponseWriter, r *http.Requ
 	app.errorResponse(w, r, http.StatusConflict, message)
 }
 
+func (app *application) preconditionFailedResponse(w http.ResponseWriter, r *http.Request) {
+	message := "the record has changed since it was last fetched, please fetch it again"
+	app.errorResponse(w, r, http.StatusPreconditionFailed, message)
+}
+
+func (app *application) notAcceptableResponse(w http.ResponseWriter, r *http.Request, message string) {
+	app.errorResponse(w, r, http.StatusNotAcceptable, message)
+}
+
+func (app *application) conflictResponse(w http.ResponseWriter, r *http.Request, message string) {
+	app.errorResponse(w, r, http.StatusConflict, message)
+}
+
 func (app *application) failedValidationResponse(w http.ResponseWriter, r *http.Request, errors map[string]string) {
 	app.errorResponse(w, r, http.StatusUnprocessableEntity, errors)
 }
+
+func (app *application) providerUnavailableResponse(w http.ResponseWriter, r *http.Request) {
+	message := "the song details provider is temporarily unavailable, please try again later"
+	app.errorResponse(w, r, http.StatusServiceUnavailable, message)
+}
+
+func (app *application) providerThrottledResponse(w http.ResponseWriter, r *http.Request) {
+	message := "provider throttled: too many requests to the song details provider, please try again later"
+	app.errorResponse(w, r, http.StatusServiceUnavailable, message)
+}
+
+func (app *application) providerErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, provider.ErrThrottled):
+		app.providerThrottledResponse(w, r)
+	case errors.Is(err, provider.ErrCircuitOpen):
+		app.providerUnavailableResponse(w, r)
+	case errors.Is(err, provider.ErrNotFound):
+		app.errorResponse(w, r, http.StatusNotFound, "the song details provider has no details for this song")
+	default:
+		app.logError(r, err)
+		app.errorResponse(w, r, http.StatusBadGateway, "the song details provider failed to process the request")
+	}
+}
@@ -0,0 +1,365 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"test_task/internal/data"
+	"test_task/internal/validator"
+)
+
+// @Summary Add a new group
+// @Description Adds a new group (artist)
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param group body object{name=string} true "Group name"
+// @Success 201 {object} envelope{group=data.Group} "Group added successfully"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 422 {object} map[string]string "Validation errors"
+// @Failure 500 {object} map[string]string "the server encountered a problem and could not process your request"
+// @Router /v1/groups [post]
+func (app *application) addGroupHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name string `json:"name"`
+	}
+
+	err := app.readJSON(w, r, &req)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		app.logger.Warn("bad request", slog.Any("error", err))
+		return
+	}
+
+	log := app.logger.With(
+		slog.String("group", req.Name))
+
+	log.Info("attempting to add a new group")
+
+	group := &data.Group{
+		Name: data.NormaliseGroupName(req.Name),
+	}
+
+	v := validator.New()
+
+	if data.ValidateGroup(v, group); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		app.logger.Warn("validation has not passed")
+		return
+	}
+
+	err = app.models.Groups.Insert(group)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrDuplicateGroup):
+			v.AddError("name", "a group with this name already exists")
+			app.failedValidationResponse(w, r, v.Errors)
+			app.logger.Warn("group is already in database", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to insert group", slog.Any("error", err))
+		}
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("Location", fmt.Sprintf("/v1/groups/%d", group.ID))
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"group": group}, headers)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to add group", slog.Any("error", err))
+		return
+	}
+
+	log.Info("group added successfully")
+}
+
+// @Summary Get a group by ID
+// @Description Retrieves a group by its ID
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param id path int true "Group ID"
+// @Success 200 {object} envelope{group=data.Group} "Successfully retrieved group"
+// @Failure 404 {object} map[string]string "The requested resource could not be found"
+// @Failure 500 {object} map[string]string "The server encountered a problem and could not process your request"
+// @Router /v1/groups/{id} [get]
+func (app *application) showGroupHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	group, err := app.models.Groups.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("group not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to get group", slog.Any("error", err))
+		}
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"group": group}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to get group", slog.Any("error", err))
+	}
+}
+
+// @Summary List groups
+// @Description Lists groups with optional name filter and pagination
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param name query string false "Group name"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Number of items per page" default(5)
+// @Param sort query string false "Sort by field" default(id) Enum(id,name,-id,-name)
+// @Success 200 {object} envelope{groups=[]data.Group,metadata=data.Metadata} "List of groups"
+// @Failure 422 {object} map[string]string "Validation errors"
+// @Failure 500 {object} map[string]string "the server encountered a problem and could not process your request"
+// @Router /v1/groups [get]
+func (app *application) listGroupsHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name string
+		data.Filters
+	}
+
+	v := validator.New()
+	qs := r.URL.Query()
+
+	req.Name = app.readString(qs, "name", "")
+
+	req.Filters.Page = app.readInt(qs, "page", 1, v)
+	req.Filters.PageSize = app.readInt(qs, "page_size", 5, v)
+	req.Filters.Sort = app.readString(qs, "sort", "id")
+	req.Filters.SortSafelist = []string{"id", "name", "-id", "-name"}
+
+	if data.ValidateFilters(v, req.Filters); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		app.logger.Warn("validation has not passed")
+		return
+	}
+
+	groups, metadata, err := app.models.Groups.GetAll(req.Name, req.Filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("error getting groups", slog.Any("error", err))
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"groups": groups, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("error getting groups", slog.Any("error", err))
+	}
+}
+
+// @Summary Rename a group
+// @Description Updates the name of a group; all its songs follow
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param id path int true "Group ID"
+// @Param group body object{name=string} true "New group name"
+// @Success 200 {object} envelope{group=data.Group} "Group updated successfully"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Group not found"
+// @Failure 422 {object} map[string]string "Validation errors"
+// @Failure 500 {object} map[string]string "the server encountered a problem and could not process your request"
+// @Router /v1/groups/{id} [patch]
+func (app *application) updateGroupHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	log := app.logger.With(
+		slog.String("group", strconv.FormatInt(id, 10)))
+
+	log.Info("attempting to edit a group")
+
+	group, err := app.models.Groups.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("group not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("error updating group", slog.Any("error", err))
+		}
+		return
+	}
+
+	var req struct {
+		Name *string `json:"name"`
+	}
+
+	err = app.readJSON(w, r, &req)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		app.logger.Warn("bad request", slog.Any("error", err))
+		return
+	}
+
+	if req.Name != nil {
+		group.Name = data.NormaliseGroupName(*req.Name)
+	}
+
+	v := validator.New()
+
+	if data.ValidateGroup(v, group); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		app.logger.Warn("validation has not passed")
+		return
+	}
+
+	err = app.models.Groups.As(app.actor(r)).Update(group)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("group not found", slog.Any("error", err))
+		case errors.Is(err, data.ErrDuplicateGroup):
+			v.AddError("name", "a group with this name already exists")
+			app.failedValidationResponse(w, r, v.Errors)
+			app.logger.Warn("group is already in database", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("error updating group", slog.Any("error", err))
+		}
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"group": group}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("error updating group", slog.Any("error", err))
+	}
+
+	log.Info("group was edited successfully")
+}
+
+// @Summary Delete a group
+// @Description Deletes a group that has no songs or albums and isn't credited on any song
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param id path int true "Group ID"
+// @Success 200 {object} map[string]string "Group successfully deleted"
+// @Failure 404 {object} map[string]string "Group not found"
+// @Failure 409 {object} map[string]string "Group still has songs or albums, or is credited on songs"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/groups/{id} [delete]
+func (app *application) deleteGroupHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	log := app.logger.With(
+		slog.String("group", strconv.FormatInt(id, 10)))
+
+	log.Info("trying to delete the group")
+
+	err = app.models.Groups.Delete(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("group not found", slog.Any("error", err))
+		case errors.Is(err, data.ErrGroupHasSongs):
+			app.conflictResponse(w, r, "the group still has songs and can't be deleted")
+			app.logger.Warn("group still has songs", slog.Any("error", err))
+		case errors.Is(err, data.ErrGroupHasCredits):
+			app.conflictResponse(w, r, "the group is still credited on songs and can't be deleted")
+			app.logger.Warn("group is still credited on songs", slog.Any("error", err))
+		case errors.Is(err, data.ErrGroupHasAlbums):
+			app.conflictResponse(w, r, "the group still has albums and can't be deleted")
+			app.logger.Warn("group still has albums", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("error deleting group", slog.Any("error", err))
+		}
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "group successfully deleted"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("error deleting group", slog.Any("error", err))
+	}
+
+	log.Info("group successfully deleted")
+}
+
+// @Summary List songs of a group
+// @Description Lists the songs of a group, including those it is credited on, with the same filters and pagination as GET /v1/songs
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param id path int true "Group ID"
+// @Param song query string false "Song name"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Number of items per page" default(5)
+// @Param sort query string false "Sort by field" default(id)
+// @Success 200 {object} envelope{songs=[]data.Song,metadata=data.Metadata} "List of songs"
+// @Failure 404 {object} map[string]string "Group not found"
+// @Failure 422 {object} map[string]string "Validation errors"
+// @Failure 500 {object} map[string]string "the server encountered a problem and could not process your request"
+// @Router /v1/groups/{id}/songs [get]
+func (app *application) listGroupSongsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	v := validator.New()
+
+	req := app.readSongFilter(r.URL.Query(), v)
+	req.GroupID = id
+
+	if data.ValidateSongFilter(v, req); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		app.logger.Warn("validation has not passed")
+		return
+	}
+
+	_, err = app.models.Groups.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("group not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to get group", slog.Any("error", err))
+		}
+		return
+	}
+
+	songs, metadata, err := app.models.Songs.GetAll(req)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("error getting songs", slog.Any("error", err))
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"songs": songs, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("error getting songs", slog.Any("error", err))
+	}
+}
@@ -3,12 +3,19 @@ package main
 import "net/http"
 
 func (app *application) healthcheckHandler(w http.ResponseWriter, r *http.Request) {
+	providers := make(map[string]string, len(app.breakers))
+	for _, b := range app.breakers {
+		providers[b.Name()] = b.State()
+	}
+
 	env := envelope{
 		"status": "available",
 		"system_info": map[string]string{
 			"environment": app.config.env,
 			"version":     version,
 		},
+		"providers": providers,
+		"cache":     app.cache.Stats(),
 	}
 
 	err := app.writeJSON(w, http.StatusOK, env, nil)
@@ -1,17 +1,20 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/julienschmidt/httprouter"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
 	"test_task/internal/data"
 	"test_task/internal/validator"
+	"unicode/utf8"
 )
 
 type envelope map[string]any
@@ -97,6 +100,17 @@ func (app *application) readIDParam(r *http.Request) (int64, error) {
 	return id, nil
 }
 
+func (app *application) readIntParam(r *http.Request, name string) (int64, error) {
+	params := httprouter.ParamsFromContext(r.Context())
+
+	i, err := strconv.ParseInt(params.ByName(name), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s parameter", name)
+	}
+
+	return i, nil
+}
+
 func (app *application) readString(qs url.Values, key string, defaultValue string) string {
 	s := qs.Get(key)
 
@@ -107,6 +121,16 @@ func (app *application) readString(qs url.Values, key string, defaultValue strin
 	return s
 }
 
+func (app *application) readCSV(qs url.Values, key string, defaultValue []string) []string {
+	csv := qs.Get(key)
+
+	if csv == "" {
+		return defaultValue
+	}
+
+	return strings.Split(csv, ",")
+}
+
 func (app *application) readInt(qs url.Values, key string, defaultValue int, v *validator.Validator) int {
 	s := qs.Get(key)
 
@@ -123,6 +147,123 @@ func (app *application) readInt(qs url.Values, key string, defaultValue int, v *
 	return i
 }
 
+func (app *application) readBool(qs url.Values, key string, defaultValue bool, v *validator.Validator) bool {
+	s := qs.Get(key)
+
+	if s == "" {
+		return defaultValue
+	}
+
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		v.AddError(key, "must be a boolean value")
+		return defaultValue
+	}
+
+	return b
+}
+
+func (app *application) readDate(qs url.Values, key string, v *validator.Validator) *data.Date {
+	s := qs.Get(key)
+
+	if s == "" {
+		return nil
+	}
+
+	d, err := data.ParseDate(s)
+	if err != nil {
+		v.AddError(key, err.Error())
+		return nil
+	}
+
+	return &d
+}
+
+// actor identifies who makes the request, taken from the X-Actor header, and
+// the request itself.
+func (app *application) actor(r *http.Request) data.Actor {
+	name := strings.TrimSpace(r.Header.Get("X-Actor"))
+	if name == "" {
+		name = "anonymous"
+	}
+
+	if utf8.RuneCountInString(name) > 255 {
+		name = string([]rune(name)[:255])
+	}
+
+	return data.Actor{Name: name, RequestID: requestID(r)}
+}
+
+// songETag is the entity tag of a version of a song.
+func songETag(song *data.Song) string {
+	return fmt.Sprintf("\"%d\"", song.Version)
+}
+
+// ifMatch reports whether the If-Match header of the request, if any, matches
+// the current entity tag of a song.
+func (app *application) ifMatch(r *http.Request, song *data.Song) bool {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return true
+	}
+
+	etag := songETag(song)
+
+	for _, value := range strings.Split(header, ",") {
+		value = strings.TrimSpace(value)
+		if value == "*" || value == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lrcMediaType returns the media type to send lyrics as LRC with, or "" if
+// the client wants JSON. The format query parameter wins over the Accept
+// header. Of the media ranges in Accept the one with the highest q-value is
+// used, the earliest among equals; ranges with q=0 are never used.
+func lrcMediaType(r *http.Request) string {
+	switch r.URL.Query().Get("format") {
+	case "lrc":
+		return "application/lrc"
+	case "json":
+		return ""
+	}
+
+	best, bestQ := "", 0.0
+
+	for _, value := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, _ := strings.Cut(value, ";")
+		mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+
+		switch mediaType {
+		case "application/lrc", "text/x-lrc", "text/plain":
+		case "application/json", "application/*", "*/*":
+			mediaType = ""
+		default:
+			continue
+		}
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, value, _ := strings.Cut(param, "=")
+			if strings.TrimSpace(strings.ToLower(key)) == "q" {
+				f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+				if err == nil {
+					q = f
+				}
+			}
+		}
+
+		if q > bestQ {
+			best, bestQ = mediaType, q
+		}
+	}
+
+	return best
+}
+
 func (app *application) background(fn func()) {
 	app.wg.Add(1)
 
@@ -131,7 +272,7 @@ func (app *application) background(fn func()) {
 
 		defer func() {
 			if err := recover(); err != nil {
-				app.logger.Error("error catching id", err)
+				app.logger.Error("error catching id", slog.Any("error", err))
 			}
 		}()
 
@@ -139,27 +280,14 @@ func (app *application) background(fn func()) {
 	}()
 }
 
-func (app *application) fetchSongDetail(song, group string) (*data.SongDetail, error) {
-	apiURL := fmt.Sprintf("https://0b3a10d5-1d0a-4465-8e41-ebbb4531677e.mock.pstmn.io/info?group=%s&song=%s", group, song)
-
-	resp, err := http.Get(apiURL)
+func (app *application) fetchSongDetail(ctx context.Context, song, group string) (*data.SongDetail, error) {
+	detail, err := app.provider.Fetch(ctx, song, group)
 	if err != nil {
-		app.logger.Error("failed to make API request", err)
-		return nil, fmt.Errorf("failed to make API request %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		app.logger.Error("unexpected status code", err)
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		app.logger.Error("failed to fetch song details",
+			slog.String("provider", app.provider.Name()),
+			slog.Any("error", err))
+		return nil, err
 	}
 
-	var detail data.SongDetail
-	err = json.NewDecoder(resp.Body).Decode(&detail)
-	if err != nil {
-		app.logger.Error("failed to parse API response", err)
-		return nil, fmt.Errorf("failed to parse API response: %w", err)
-	}
-
-	return &detail, nil
-
+	return detail, nil
 }
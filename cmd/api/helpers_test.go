@@ -0,0 +1,37 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLRCMediaType(t *testing.T) {
+	tests := []struct {
+		target string
+		accept string
+		want   string
+	}{
+		{"/v1/song/1/lyrics", "", ""},
+		{"/v1/song/1/lyrics", "*/*", ""},
+		{"/v1/song/1/lyrics", "application/lrc", "application/lrc"},
+		{"/v1/song/1/lyrics", "text/plain; charset=utf-8", "text/plain"},
+		{"/v1/song/1/lyrics", "application/json, application/lrc", ""},
+		{"/v1/song/1/lyrics", "application/json;q=0.5, application/lrc", "application/lrc"},
+		{"/v1/song/1/lyrics", "text/plain;q=0.9, */*;q=0.1", "text/plain"},
+		{"/v1/song/1/lyrics", "application/lrc;q=0", ""},
+		{"/v1/song/1/lyrics", "text/html, text/x-lrc", "text/x-lrc"},
+		{"/v1/song/1/lyrics?format=lrc", "application/json", "application/lrc"},
+		{"/v1/song/1/lyrics?format=json", "application/lrc", ""},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", tt.target, nil)
+		if tt.accept != "" {
+			r.Header.Set("Accept", tt.accept)
+		}
+
+		if got := lrcMediaType(r); got != tt.want {
+			t.Errorf("lrcMediaType(%s, Accept: %q) = %q, want %q", tt.target, tt.accept, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"test_task/internal/data"
+	"test_task/internal/validator"
+)
+
+// @Summary Set a platform link
+// @Description Adds or replaces the link of a song on a platform. The first link of a song becomes its primary link
+// @Tags Links
+// @Accept json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param platform path string true "Platform" Enums(youtube,spotify,apple_music,bandcamp)
+// @Param link body object{url=string,primary=bool} true "Link URL and whether it is the primary link"
+// @Success 200 {object} envelope{song=data.Song} "Song with its links"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Song not found"
+// @Failure 422 {object} map[string]string "Validation errors"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/song/{id}/links/{platform} [put]
+func (app *application) setSongLinkHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var req struct {
+		URL     string `json:"url"`
+		Primary bool   `json:"primary"`
+	}
+
+	err = app.readJSON(w, r, &req)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		app.logger.Warn("bad request", slog.Any("error", err))
+		return
+	}
+
+	link := &data.SongLink{
+		Platform: httprouter.ParamsFromContext(r.Context()).ByName("platform"),
+		URL:      req.URL,
+		Primary:  req.Primary,
+	}
+
+	v := validator.New()
+
+	if data.ValidateSongLink(v, link); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		app.logger.Warn("validation has not passed")
+		return
+	}
+
+	log := app.logger.With(
+		slog.String("song", strconv.FormatInt(id, 10)),
+		slog.String("platform", link.Platform))
+
+	log.Info("attempting to set song link")
+
+	err = app.models.SongLinks.As(app.actor(r)).Set(id, link)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("song not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to set song link", slog.Any("error", err))
+		}
+		return
+	}
+
+	app.writeSong(w, r, id)
+
+	log.Info("song link set successfully")
+}
+
+// @Summary Remove a platform link
+// @Description Removes the link of a song on a platform. If it was the primary link, the oldest remaining link takes its place
+// @Tags Links
+// @Accept json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param platform path string true "Platform" Enums(youtube,spotify,apple_music,bandcamp)
+// @Success 200 {object} envelope{song=data.Song} "Song with its remaining links"
+// @Failure 404 {object} map[string]string "Song or link not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/song/{id}/links/{platform} [delete]
+func (app *application) removeSongLinkHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	platform := httprouter.ParamsFromContext(r.Context()).ByName("platform")
+
+	log := app.logger.With(
+		slog.String("song", strconv.FormatInt(id, 10)),
+		slog.String("platform", platform))
+
+	log.Info("attempting to remove song link")
+
+	err = app.models.SongLinks.As(app.actor(r)).Remove(id, platform)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("song link not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to remove song link", slog.Any("error", err))
+		}
+		return
+	}
+
+	app.writeSong(w, r, id)
+
+	log.Info("song link removed successfully")
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"test_task/internal/data"
+	"test_task/internal/validator"
+)
+
+// @Summary List lyrics revisions
+// @Description Lists the lyrics revisions of a song, newest first
+// @Tags Lyrics
+// @Accept json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Number of revisions per page" default(20)
+// @Success 200 {object} envelope{revisions=[]data.LyricsRevision,metadata=data.Metadata} "Lyrics revisions"
+// @Failure 404 {object} map[string]string "Song not found"
+// @Failure 422 {object} map[string]string "Validation errors"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/song/{id}/lyrics/revisions [get]
+func (app *application) listLyricsRevisionsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var filters data.Filters
+
+	v := validator.New()
+	qs := r.URL.Query()
+
+	filters.Page = app.readInt(qs, "page", 1, v)
+	filters.PageSize = app.readInt(qs, "page_size", 20, v)
+
+	if data.ValidatePagination(v, filters); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		app.logger.Warn("validation has not passed")
+		return
+	}
+
+	_, err = app.models.Songs.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("song not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to get song", slog.Any("error", err))
+		}
+		return
+	}
+
+	revisions, metadata, err := app.models.LyricsRevisions.GetAll(id, filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to get lyrics revisions", slog.Any("error", err))
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"revisions": revisions, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to get lyrics revisions", slog.Any("error", err))
+	}
+}
+
+// @Summary Get a lyrics revision
+// @Description Retrieves a single lyrics revision of a song with its full text
+// @Tags Lyrics
+// @Accept json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param rev path int true "Revision number"
+// @Success 200 {object} envelope{revision=data.LyricsRevision} "Lyrics revision"
+// @Failure 404 {object} map[string]string "Song or revision not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/song/{id}/lyrics/revisions/{rev} [get]
+func (app *application) showLyricsRevisionHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	rev, err := app.readIntParam(r, "rev")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	_, err = app.models.Songs.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("song not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to get song", slog.Any("error", err))
+		}
+		return
+	}
+
+	revision, err := app.models.LyricsRevisions.Get(id, int(rev))
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("lyrics revision not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to get lyrics revision", slog.Any("error", err))
+		}
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"revision": revision}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to get lyrics revision", slog.Any("error", err))
+	}
+}
+
+// @Summary Restore a lyrics revision
+// @Description Makes the text of a lyrics revision the current lyrics of the song; the restore is recorded as a new revision
+// @Tags Lyrics
+// @Accept json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param rev path int true "Revision number"
+// @Success 200 {object} envelope{song=data.Song} "Song with restored lyrics"
+// @Failure 404 {object} map[string]string "Song or revision not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/song/{id}/lyrics/revisions/{rev}/restore [post]
+func (app *application) restoreLyricsRevisionHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	rev, err := app.readIntParam(r, "rev")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	log := app.logger.With(
+		slog.String("song", strconv.FormatInt(id, 10)),
+		slog.Int64("revision", rev))
+
+	log.Info("attempting to restore lyrics revision")
+
+	_, err = app.models.Songs.As(app.actor(r)).RestoreLyrics(id, int(rev))
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("song or lyrics revision not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to restore lyrics revision", slog.Any("error", err))
+		}
+		return
+	}
+
+	song, err := app.models.Songs.Get(id)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to get song", slog.Any("error", err))
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"song": song}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to restore lyrics revision", slog.Any("error", err))
+	}
+
+	log.Info("lyrics revision restored successfully")
+}
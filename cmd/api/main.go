@@ -10,22 +10,69 @@ import (
 	"os"
 	"sync"
 	"test_task/internal/data"
+	"test_task/internal/provider"
 	"time"
 )
 
 const version = "1.0.0"
 
 type config struct {
-	port  int
-	env   string
-	dbDSN string
+	port     int
+	env      string
+	dbDSN    string
+	provider struct {
+		kind     string
+		url      string
+		timeout  time.Duration
+		headers  string
+		fixtures string
+		retry    struct {
+			attempts int
+			base     time.Duration
+			max      time.Duration
+		}
+		breaker struct {
+			threshold int
+			cooldown  time.Duration
+		}
+		rateLimit struct {
+			rate  float64
+			burst int
+		}
+		cache struct {
+			size        int
+			ttl         time.Duration
+			negativeTTL time.Duration
+		}
+	}
+	enrichment struct {
+		workers     int
+		maxAttempts int
+		poll        time.Duration
+		lease       time.Duration
+	}
+	resync struct {
+		interval time.Duration
+		maxAge   time.Duration
+		batch    int
+		backoff  time.Duration
+	}
+	trash struct {
+		retention     time.Duration
+		purgeInterval time.Duration
+	}
 }
 
 type application struct {
-	config config
-	logger *slog.Logger
-	models data.Models
-	wg     sync.WaitGroup
+	config   config
+	logger   *slog.Logger
+	models   data.Models
+	provider provider.SongDetailProvider
+	breakers []*provider.Breaker
+	cache    *provider.Cache
+	wg       sync.WaitGroup
+
+	enrichmentWake chan struct{}
 }
 
 // @title Music Library
@@ -50,10 +97,49 @@ func main() {
 	flag.StringVar(&cfg.env, "env", "development", "Environment (development|staging|production)")
 	flag.StringVar(&cfg.dbDSN, "db-dsn", "", "PostgreSQL DSN")
 
+	flag.StringVar(&cfg.provider.kind, "provider", "http", "Song detail provider (http|fake)")
+	flag.StringVar(&cfg.provider.url, "provider-url", "", "Song detail provider base URLs in priority order (\"url\" or \"name=url\", comma-separated)")
+	flag.DurationVar(&cfg.provider.timeout, "provider-timeout", 5*time.Second, "Song detail provider request timeout")
+	flag.StringVar(&cfg.provider.headers, "provider-headers", "", "Extra provider request headers (\"Key: Value, Key2: Value2\")")
+	flag.StringVar(&cfg.provider.fixtures, "provider-fixtures", "", "JSON fixtures file for the fake provider")
+	flag.IntVar(&cfg.provider.retry.attempts, "provider-retry-attempts", 3, "Maximum attempts per provider request")
+	flag.DurationVar(&cfg.provider.retry.base, "provider-retry-base", 200*time.Millisecond, "Initial retry backoff")
+	flag.DurationVar(&cfg.provider.retry.max, "provider-retry-max", 2*time.Second, "Maximum retry backoff")
+	flag.IntVar(&cfg.provider.breaker.threshold, "provider-breaker-threshold", 5, "Consecutive failures before the circuit breaker opens")
+	flag.DurationVar(&cfg.provider.breaker.cooldown, "provider-breaker-cooldown", 30*time.Second, "How long the circuit breaker stays open")
+	flag.Float64Var(&cfg.provider.rateLimit.rate, "provider-rate", 10, "Maximum provider requests per second (0 disables the limit)")
+	flag.IntVar(&cfg.provider.rateLimit.burst, "provider-burst", 5, "Maximum provider request burst")
+	flag.IntVar(&cfg.provider.cache.size, "provider-cache-size", 1000, "Maximum cached provider answers (0 disables the cache)")
+	flag.DurationVar(&cfg.provider.cache.ttl, "provider-cache-ttl", time.Hour, "How long provider answers are cached")
+	flag.DurationVar(&cfg.provider.cache.negativeTTL, "provider-cache-negative-ttl", 5*time.Minute, "How long \"not found\" provider answers are cached")
+
+	flag.IntVar(&cfg.enrichment.workers, "enrich-workers", 4, "Number of enrichment workers")
+	flag.IntVar(&cfg.enrichment.maxAttempts, "enrich-max-attempts", 5, "Attempts per enrichment job before the song is marked as failed")
+	flag.DurationVar(&cfg.enrichment.poll, "enrich-poll", time.Second, "How often idle workers look for due enrichment jobs")
+	flag.DurationVar(&cfg.enrichment.lease, "enrich-lease", 2*time.Minute, "How long a worker holds an enrichment job")
+
+	flag.DurationVar(&cfg.resync.interval, "resync-interval", time.Hour, "How often stale song details are re-fetched (0 disables the resync)")
+	flag.DurationVar(&cfg.resync.maxAge, "resync-max-age", 7*24*time.Hour, "Age after which song details are considered stale")
+	flag.IntVar(&cfg.resync.batch, "resync-batch", 50, "Maximum songs re-fetched per resync run")
+	flag.DurationVar(&cfg.resync.backoff, "resync-backoff", time.Hour, "Delay before a song whose resync failed is tried again, doubled on each further failure")
+
+	flag.DurationVar(&cfg.trash.retention, "trash-retention", 30*24*time.Hour, "How long deleted songs stay in the trash before they are purged")
+	flag.DurationVar(&cfg.trash.purgeInterval, "trash-purge-interval", time.Hour, "How often the trash is purged (0 disables the purge)")
+
+	flag.Parse()
+
 	if cfg.dbDSN == "" {
 		cfg.dbDSN = os.Getenv("DB_DSN")
 	}
 
+	if cfg.provider.url == "" {
+		cfg.provider.url = os.Getenv("PROVIDER_URL")
+	}
+
+	if cfg.provider.headers == "" {
+		cfg.provider.headers = os.Getenv("PROVIDER_HEADERS")
+	}
+
 	db, err := openDB(cfg)
 	if err != nil {
 		log.Error("Fatal error occurred",
@@ -69,6 +155,17 @@ func main() {
 		config: cfg,
 		logger: log,
 		models: data.NewModels(db),
+
+		enrichmentWake: make(chan struct{}, 1),
+	}
+
+	err = app.setupProvider()
+	if err != nil {
+		log.Error("Fatal error occurred",
+			"error", err.Error(),
+			"level", "fatal")
+
+		os.Exit(1)
 	}
 
 	err = app.serve()
@@ -0,0 +1,530 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"test_task/internal/data"
+	"test_task/internal/validator"
+)
+
+// @Summary Create a playlist
+// @Description Creates an empty playlist
+// @Tags Playlists
+// @Accept json
+// @Produce json
+// @Param playlist body object{name=string,description=string} true "Playlist name and optional description"
+// @Success 201 {object} envelope{playlist=data.Playlist} "Playlist created successfully"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 422 {object} map[string]string "Validation errors"
+// @Failure 500 {object} map[string]string "the server encountered a problem and could not process your request"
+// @Router /v1/playlists [post]
+func (app *application) addPlaylistHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+
+	err := app.readJSON(w, r, &req)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		app.logger.Warn("bad request", slog.Any("error", err))
+		return
+	}
+
+	log := app.logger.With(
+		slog.String("playlist", req.Name))
+
+	log.Info("attempting to create a playlist")
+
+	playlist := &data.Playlist{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+
+	v := validator.New()
+
+	if data.ValidatePlaylist(v, playlist); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		app.logger.Warn("validation has not passed")
+		return
+	}
+
+	err = app.models.Playlists.Insert(playlist)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to insert playlist", slog.Any("error", err))
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("Location", fmt.Sprintf("/v1/playlists/%d", playlist.ID))
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"playlist": playlist}, headers)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to create playlist", slog.Any("error", err))
+		return
+	}
+
+	log.Info("playlist created successfully")
+}
+
+// @Summary Get a playlist by ID
+// @Description Retrieves a playlist by its ID
+// @Tags Playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Success 200 {object} envelope{playlist=data.Playlist} "Successfully retrieved playlist"
+// @Failure 404 {object} map[string]string "The requested resource could not be found"
+// @Failure 500 {object} map[string]string "The server encountered a problem and could not process your request"
+// @Router /v1/playlists/{id} [get]
+func (app *application) showPlaylistHandler(w http.ResponseWriter, r *http.Request) {
+	playlist, ok := app.getPlaylist(w, r)
+	if !ok {
+		return
+	}
+
+	err := app.writeJSON(w, http.StatusOK, envelope{"playlist": playlist}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to get playlist", slog.Any("error", err))
+	}
+}
+
+// @Summary List playlists
+// @Description Lists playlists with optional filters and pagination
+// @Tags Playlists
+// @Accept json
+// @Produce json
+// @Param name query string false "Playlist name"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Number of items per page" default(5)
+// @Param sort query string false "Sort by field" default(id) Enum(id,name,updated_at,-id,-name,-updated_at)
+// @Success 200 {object} envelope{playlists=[]data.Playlist,metadata=data.Metadata} "List of playlists"
+// @Failure 422 {object} map[string]string "Validation errors"
+// @Failure 500 {object} map[string]string "the server encountered a problem and could not process your request"
+// @Router /v1/playlists [get]
+func (app *application) listPlaylistsHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name string
+		data.Filters
+	}
+
+	v := validator.New()
+	qs := r.URL.Query()
+
+	req.Name = app.readString(qs, "name", "")
+
+	req.Filters.Page = app.readInt(qs, "page", 1, v)
+	req.Filters.PageSize = app.readInt(qs, "page_size", 5, v)
+	req.Filters.Sort = app.readString(qs, "sort", "id")
+	req.Filters.SortSafelist = []string{"id", "name", "updated_at", "-id", "-name", "-updated_at"}
+
+	if data.ValidateFilters(v, req.Filters); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		app.logger.Warn("validation has not passed")
+		return
+	}
+
+	playlists, metadata, err := app.models.Playlists.GetAll(req.Name, req.Filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("error getting playlists", slog.Any("error", err))
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"playlists": playlists, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("error getting playlists", slog.Any("error", err))
+	}
+}
+
+// @Summary Update a playlist
+// @Description Updates the name and description of a playlist
+// @Tags Playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Param playlist body object{name=string,description=string} true "New name and description (optional)"
+// @Success 200 {object} envelope{playlist=data.Playlist} "Playlist updated successfully"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Playlist not found"
+// @Failure 422 {object} map[string]string "Validation errors"
+// @Failure 500 {object} map[string]string "the server encountered a problem and could not process your request"
+// @Router /v1/playlists/{id} [patch]
+func (app *application) updatePlaylistHandler(w http.ResponseWriter, r *http.Request) {
+	playlist, ok := app.getPlaylist(w, r)
+	if !ok {
+		return
+	}
+
+	log := app.logger.With(
+		slog.String("playlist", strconv.FormatInt(playlist.ID, 10)))
+
+	log.Info("attempting to edit a playlist")
+
+	var req struct {
+		Name        *string `json:"name"`
+		Description *string `json:"description"`
+	}
+
+	err := app.readJSON(w, r, &req)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		app.logger.Warn("bad request", slog.Any("error", err))
+		return
+	}
+
+	if req.Name != nil {
+		playlist.Name = *req.Name
+	}
+
+	if req.Description != nil {
+		playlist.Description = *req.Description
+	}
+
+	v := validator.New()
+
+	if data.ValidatePlaylist(v, playlist); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		app.logger.Warn("validation has not passed")
+		return
+	}
+
+	err = app.models.Playlists.Update(playlist)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("playlist not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("error updating playlist", slog.Any("error", err))
+		}
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"playlist": playlist}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("error updating playlist", slog.Any("error", err))
+	}
+
+	log.Info("playlist was edited successfully")
+}
+
+// @Summary Delete a playlist
+// @Description Deletes a playlist with all its entries; the songs themselves are kept
+// @Tags Playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Success 200 {object} map[string]string "Playlist successfully deleted"
+// @Failure 404 {object} map[string]string "Playlist not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/playlists/{id} [delete]
+func (app *application) deletePlaylistHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	err = app.models.Playlists.Delete(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("playlist not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("error deleting playlist", slog.Any("error", err))
+		}
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "playlist successfully deleted"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("error deleting playlist", slog.Any("error", err))
+	}
+}
+
+// @Summary List playlist entries
+// @Description Lists the entries of a playlist in position order; songs in the trash are left out
+// @Tags Playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Number of items per page" default(20)
+// @Success 200 {object} envelope{items=[]data.PlaylistItem,metadata=data.Metadata} "Playlist entries"
+// @Failure 404 {object} map[string]string "Playlist not found"
+// @Failure 422 {object} map[string]string "Validation errors"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/playlists/{id}/items [get]
+func (app *application) listPlaylistItemsHandler(w http.ResponseWriter, r *http.Request) {
+	var filters data.Filters
+
+	v := validator.New()
+	qs := r.URL.Query()
+
+	filters.Page = app.readInt(qs, "page", 1, v)
+	filters.PageSize = app.readInt(qs, "page_size", 20, v)
+
+	if data.ValidatePagination(v, filters); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		app.logger.Warn("validation has not passed")
+		return
+	}
+
+	playlist, ok := app.getPlaylist(w, r)
+	if !ok {
+		return
+	}
+
+	items, metadata, err := app.models.Playlists.GetItems(playlist.ID, filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("error getting playlist items", slog.Any("error", err))
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"items": items, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("error getting playlist items", slog.Any("error", err))
+	}
+}
+
+// @Summary Add a song to a playlist
+// @Description Inserts a song at the given position, moving the following entries down; without a position the song is appended
+// @Tags Playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Param item body object{song_id=int,position=int} true "Song ID and optional position"
+// @Success 201 {object} envelope{item=data.PlaylistItem} "Song added to the playlist"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Playlist not found"
+// @Failure 422 {object} map[string]string "Validation errors"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/playlists/{id}/items [post]
+func (app *application) addPlaylistItemHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var req struct {
+		SongID   int64 `json:"song_id"`
+		Position *int  `json:"position"`
+	}
+
+	err = app.readJSON(w, r, &req)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		app.logger.Warn("bad request", slog.Any("error", err))
+		return
+	}
+
+	log := app.logger.With(
+		slog.String("playlist", strconv.FormatInt(id, 10)),
+		slog.Int64("song", req.SongID))
+
+	v := validator.New()
+
+	position := 0
+	if req.Position != nil {
+		position = *req.Position
+		data.ValidatePosition(v, "position", position)
+	}
+
+	song, err := app.models.Songs.Get(req.SongID)
+	switch {
+	case errors.Is(err, data.ErrNoRecordFound):
+		v.AddError("song_id", "song does not exist")
+	case err != nil:
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to get song", slog.Any("error", err))
+		return
+	}
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		app.logger.Warn("validation has not passed")
+		return
+	}
+
+	item := &data.PlaylistItem{Position: position, SongID: song.ID, Song: song}
+
+	err = app.models.Playlists.InsertItem(id, item)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("playlist not found", slog.Any("error", err))
+		case errors.Is(err, data.ErrPositionOutOfRange):
+			v.AddError("position", "must not be past the end of the playlist")
+			app.failedValidationResponse(w, r, v.Errors)
+			app.logger.Warn("validation has not passed", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to add playlist item", slog.Any("error", err))
+		}
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"item": item}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to add playlist item", slog.Any("error", err))
+	}
+
+	log.Info("song added to playlist", slog.Int("position", item.Position))
+}
+
+// @Summary Move a playlist entry
+// @Description Moves the entry at a position to another position, shifting the entries in between
+// @Tags Playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Param position path int true "Current position"
+// @Param move body object{position=int} true "New position"
+// @Success 200 {object} map[string]string "Entry moved successfully"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Playlist or entry not found"
+// @Failure 422 {object} map[string]string "Validation errors"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/playlists/{id}/items/{position} [patch]
+func (app *application) movePlaylistItemHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	from, err := app.readIntParam(r, "position")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var req struct {
+		Position int `json:"position"`
+	}
+
+	err = app.readJSON(w, r, &req)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		app.logger.Warn("bad request", slog.Any("error", err))
+		return
+	}
+
+	v := validator.New()
+
+	if data.ValidatePosition(v, "position", req.Position); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		app.logger.Warn("validation has not passed")
+		return
+	}
+
+	err = app.models.Playlists.MoveItem(id, int(from), req.Position)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("playlist item not found", slog.Any("error", err))
+		case errors.Is(err, data.ErrPositionOutOfRange):
+			v.AddError("position", "must not be past the end of the playlist")
+			app.failedValidationResponse(w, r, v.Errors)
+			app.logger.Warn("validation has not passed", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to move playlist item", slog.Any("error", err))
+		}
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "entry successfully moved"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to move playlist item", slog.Any("error", err))
+	}
+}
+
+// @Summary Remove a playlist entry
+// @Description Removes the entry at a position, moving the following entries up
+// @Tags Playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Param position path int true "Position"
+// @Success 200 {object} map[string]string "Entry successfully removed"
+// @Failure 404 {object} map[string]string "Playlist or entry not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/playlists/{id}/items/{position} [delete]
+func (app *application) removePlaylistItemHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	position, err := app.readIntParam(r, "position")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	err = app.models.Playlists.RemoveItem(id, int(position))
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("playlist item not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("error removing playlist item", slog.Any("error", err))
+		}
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "entry successfully removed"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("error removing playlist item", slog.Any("error", err))
+	}
+}
+
+// getPlaylist loads the playlist named by the id URL parameter, writing the
+// error response itself when that fails.
+func (app *application) getPlaylist(w http.ResponseWriter, r *http.Request) (*data.Playlist, bool) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return nil, false
+	}
+
+	playlist, err := app.models.Playlists.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("playlist not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to get playlist", slog.Any("error", err))
+		}
+		return nil, false
+	}
+
+	return playlist, true
+}
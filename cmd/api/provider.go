@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"test_task/internal/provider"
+)
+
+// setupProvider builds the song detail provider from the config: every
+// configured source is guarded by its own outbound rate limiter, circuit
+// breaker and retries, the sources are merged by a chain in the configured
+// order, and a cache sits in front of the chain. The breaker sits above the
+// limiter, so an open circuit fails fast without spending rate limit tokens.
+func (app *application) setupProvider() error {
+	cfg := app.config
+
+	sources, err := newProviderSources(cfg)
+	if err != nil {
+		return err
+	}
+
+	chain := make([]provider.SongDetailProvider, 0, len(sources))
+	app.breakers = make([]*provider.Breaker, 0, len(sources))
+
+	for _, source := range sources {
+		limiter := provider.NewRateLimiter(source, provider.RateLimitConfig{
+			Rate:  cfg.provider.rateLimit.rate,
+			Burst: cfg.provider.rateLimit.burst,
+		})
+
+		breaker := provider.NewBreaker(limiter, provider.BreakerConfig{
+			FailureThreshold: cfg.provider.breaker.threshold,
+			Cooldown:         cfg.provider.breaker.cooldown,
+		})
+
+		chain = append(chain, provider.WithRetry(breaker, provider.RetryConfig{
+			MaxAttempts: cfg.provider.retry.attempts,
+			BaseDelay:   cfg.provider.retry.base,
+			MaxDelay:    cfg.provider.retry.max,
+		}))
+
+		app.breakers = append(app.breakers, breaker)
+	}
+
+	app.cache = provider.NewCache(provider.NewChain(chain...), provider.CacheConfig{
+		Size:        cfg.provider.cache.size,
+		TTL:         cfg.provider.cache.ttl,
+		NegativeTTL: cfg.provider.cache.negativeTTL,
+	})
+
+	app.provider = app.cache
+
+	return nil
+}
+
+func newProviderSources(cfg config) ([]provider.SongDetailProvider, error) {
+	switch cfg.provider.kind {
+	case "fake":
+		if cfg.provider.fixtures == "" {
+			return []provider.SongDetailProvider{provider.NewFake()}, nil
+		}
+
+		fake, err := provider.LoadFixtures(cfg.provider.fixtures)
+		if err != nil {
+			return nil, err
+		}
+
+		return []provider.SongDetailProvider{fake}, nil
+	case "http":
+		headers, err := provider.ParseHeaders(cfg.provider.headers)
+		if err != nil {
+			return nil, err
+		}
+
+		var sources []provider.SongDetailProvider
+		names := make(map[string]bool)
+
+		for _, entry := range strings.Split(cfg.provider.url, ",") {
+			entry = strings.TrimSpace(entry)
+			if entry == "" {
+				continue
+			}
+
+			// Entries are either "url" or "name=url".
+			name, baseURL, ok := strings.Cut(entry, "=")
+			if !ok || strings.ContainsAny(name, ":/") {
+				name, baseURL = "", entry
+			}
+
+			source, err := provider.NewHTTP(provider.HTTPConfig{
+				Name:    name,
+				BaseURL: baseURL,
+				Timeout: cfg.provider.timeout,
+				Headers: headers,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			if names[source.Name()] {
+				return nil, fmt.Errorf("duplicate provider name %q", source.Name())
+			}
+			names[source.Name()] = true
+
+			sources = append(sources, source)
+		}
+
+		if len(sources) == 0 {
+			return nil, errors.New("no provider url configured")
+		}
+
+		return sources, nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q", cfg.provider.kind)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewProviderSources(t *testing.T) {
+	var cfg config
+	cfg.provider.kind = "http"
+	cfg.provider.url = "primary=http://meta.example.com/api, http://backup.example.com:8080"
+	cfg.provider.headers = "Authorization: Bearer secret"
+	cfg.provider.timeout = 5 * time.Second
+
+	sources, err := newProviderSources(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, source := range sources {
+		names = append(names, source.Name())
+	}
+
+	if len(names) != 2 || names[0] != "primary" || names[1] != "backup.example.com:8080" {
+		t.Errorf("sources = %v, want [primary backup.example.com:8080]", names)
+	}
+}
+
+func TestNewProviderSourcesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    string
+		url     string
+		headers string
+	}{
+		{"unknown kind", "grpc", "http://meta.example.com", ""},
+		{"no url", "http", " , ", ""},
+		{"invalid url", "http", "meta.example.com", ""},
+		{"duplicate name", "http", "a=http://one.example.com,a=http://two.example.com", ""},
+		{"malformed headers", "http", "http://meta.example.com", "Authorization"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg config
+			cfg.provider.kind = tt.kind
+			cfg.provider.url = tt.url
+			cfg.provider.headers = tt.headers
+
+			_, err := newProviderSources(cfg)
+			if err == nil {
+				t.Error("newProviderSources succeeded, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"test_task/internal/data"
+	"test_task/internal/validator"
+)
+
+// @Summary List related songs
+// @Description Lists the songs related to a song, grouped by relation type. Relations from other songs to this one appear under covered_by, remixed_by, live_versions and sampled_by
+// @Tags Relations
+// @Accept json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Success 200 {object} envelope{related=map[string][]data.Song} "Related songs by relation type"
+// @Failure 404 {object} map[string]string "Song not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/song/{id}/related [get]
+func (app *application) listRelatedSongsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	_, err = app.models.Songs.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("song not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to get song", slog.Any("error", err))
+		}
+		return
+	}
+
+	app.writeRelated(w, r, id, http.StatusOK)
+}
+
+// @Summary Relate two songs
+// @Description Records that a song is a cover, remix, live version or sample of another song
+// @Tags Relations
+// @Accept json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param relation body object{type=string,song_id=int} true "Relation type (cover_of, remix_of, live_version_of, sample_of) and the related song"
+// @Success 201 {object} envelope{related=map[string][]data.Song} "Related songs by relation type"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Song not found"
+// @Failure 409 {object} map[string]string "The songs are already related this way, or the related song is related back the same way"
+// @Failure 422 {object} map[string]string "Validation errors"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/song/{id}/related [post]
+func (app *application) linkSongsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var req struct {
+		Type   string `json:"type"`
+		SongID int64  `json:"song_id"`
+	}
+
+	err = app.readJSON(w, r, &req)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		app.logger.Warn("bad request", slog.Any("error", err))
+		return
+	}
+
+	v := validator.New()
+
+	if data.ValidateRelation(v, id, req.SongID, req.Type); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		app.logger.Warn("validation has not passed")
+		return
+	}
+
+	log := app.logger.With(
+		slog.String("song", strconv.FormatInt(id, 10)),
+		slog.String("type", req.Type),
+		slog.Int64("related", req.SongID))
+
+	log.Info("attempting to relate songs")
+
+	err = app.models.SongRelations.As(app.actor(r)).Link(id, req.SongID, req.Type)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("song not found", slog.Any("error", err))
+		case errors.Is(err, data.ErrDuplicateRelation):
+			app.conflictResponse(w, r, "the songs are already related this way")
+			app.logger.Warn("songs are already related", slog.Any("error", err))
+		case errors.Is(err, data.ErrContradictoryRelation):
+			app.conflictResponse(w, r, "the related song is already related back this way")
+			app.logger.Warn("songs are already related the other way", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to relate songs", slog.Any("error", err))
+		}
+		return
+	}
+
+	app.writeRelated(w, r, id, http.StatusCreated)
+
+	log.Info("songs related successfully")
+}
+
+// @Summary Unrelate two songs
+// @Description Removes a relation from a song to another song
+// @Tags Relations
+// @Accept json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param type path string true "Relation type" Enums(cover_of,remix_of,live_version_of,sample_of)
+// @Param related_id path int true "Related song ID"
+// @Success 200 {object} envelope{related=map[string][]data.Song} "Related songs by relation type"
+// @Failure 404 {object} map[string]string "Song or relation not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/song/{id}/related/{type}/{related_id} [delete]
+func (app *application) unlinkSongsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	relatedID, err := app.readIntParam(r, "related_id")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	relation := httprouter.ParamsFromContext(r.Context()).ByName("type")
+
+	err = app.models.SongRelations.As(app.actor(r)).Unlink(id, relatedID, relation)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("song relation not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to unrelate songs", slog.Any("error", err))
+		}
+		return
+	}
+
+	app.writeRelated(w, r, id, http.StatusOK)
+}
+
+func (app *application) writeRelated(w http.ResponseWriter, r *http.Request, id int64, status int) {
+	related, err := app.models.SongRelations.GetRelated(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("song not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to get related songs", slog.Any("error", err))
+		}
+		return
+	}
+
+	err = app.writeJSON(w, status, envelope{"related": related}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to get related songs", slog.Any("error", err))
+	}
+}
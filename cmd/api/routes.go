@@ -18,12 +18,63 @@ func (app *application) routes() http.Handler {
 	router.HandlerFunc(http.MethodGet, "/v1/song/:id", app.showSongHandler)
 	router.HandlerFunc(http.MethodPatch, "/v1/song/:id", app.updateSongHandler)
 	router.HandlerFunc(http.MethodDelete, "/v1/song/:id", app.deleteSongHandler)
+	router.HandlerFunc(http.MethodPost, "/v1/song/:id/restore", app.restoreSongHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/song/:id/history", app.songHistoryHandler)
+	router.HandlerFunc(http.MethodPut, "/v1/song/:id/credits", app.setSongCreditsHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/song/:id/related", app.listRelatedSongsHandler)
+	router.HandlerFunc(http.MethodPost, "/v1/song/:id/related", app.linkSongsHandler)
+	router.HandlerFunc(http.MethodDelete, "/v1/song/:id/related/:type/:related_id", app.unlinkSongsHandler)
+	router.HandlerFunc(http.MethodPut, "/v1/song/:id/links/:platform", app.setSongLinkHandler)
+	router.HandlerFunc(http.MethodDelete, "/v1/song/:id/links/:platform", app.removeSongLinkHandler)
+	router.HandlerFunc(http.MethodPut, "/v1/song/:id/tags/:name", app.attachTagHandler)
+	router.HandlerFunc(http.MethodDelete, "/v1/song/:id/tags/:name", app.detachTagHandler)
+	router.HandlerFunc(http.MethodPut, "/v1/song/:id/genres/:name", app.attachGenreHandler)
+	router.HandlerFunc(http.MethodDelete, "/v1/song/:id/genres/:name", app.detachGenreHandler)
 
 	router.HandlerFunc(http.MethodGet, "/v1/song/:id/lyrics", app.showLyricsHandler)
+	router.HandlerFunc(http.MethodPut, "/v1/song/:id/lyrics", app.uploadLyricsHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/song/:id/lyrics/revisions", app.listLyricsRevisionsHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/song/:id/lyrics/revisions/:rev", app.showLyricsRevisionHandler)
+	router.HandlerFunc(http.MethodPost, "/v1/song/:id/lyrics/revisions/:rev/restore", app.restoreLyricsRevisionHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/song/:id/translations", app.listTranslationsHandler)
+	router.HandlerFunc(http.MethodPut, "/v1/song/:id/translations/:lang", app.setTranslationHandler)
+	router.HandlerFunc(http.MethodDelete, "/v1/song/:id/translations/:lang", app.removeTranslationHandler)
+	router.HandlerFunc(http.MethodPost, "/v1/song/:id/refresh", app.refreshSongHandler)
 
 	router.HandlerFunc(http.MethodGet, "/v1/songs", app.listSongsHandler)
+	router.HandlerFunc(http.MethodPost, "/v1/songs/refresh", app.refreshSongsHandler)
+
+	router.HandlerFunc(http.MethodGet, "/v1/trash", app.listTrashHandler)
+
+	router.HandlerFunc(http.MethodGet, "/v1/audit", app.listAuditHandler)
+
+	router.HandlerFunc(http.MethodGet, "/v1/tags", app.listTagsHandler)
+
+	router.HandlerFunc(http.MethodGet, "/v1/groups", app.listGroupsHandler)
+	router.HandlerFunc(http.MethodPost, "/v1/groups", app.addGroupHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/groups/:id", app.showGroupHandler)
+	router.HandlerFunc(http.MethodPatch, "/v1/groups/:id", app.updateGroupHandler)
+	router.HandlerFunc(http.MethodDelete, "/v1/groups/:id", app.deleteGroupHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/groups/:id/songs", app.listGroupSongsHandler)
+
+	router.HandlerFunc(http.MethodGet, "/v1/albums", app.listAlbumsHandler)
+	router.HandlerFunc(http.MethodPost, "/v1/albums", app.addAlbumHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/albums/:id", app.showAlbumHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/albums/:id/tracks", app.listAlbumTracksHandler)
+	router.HandlerFunc(http.MethodPut, "/v1/albums/:id/tracks/:number", app.setAlbumTrackHandler)
+	router.HandlerFunc(http.MethodDelete, "/v1/albums/:id/tracks/:number", app.removeAlbumTrackHandler)
+
+	router.HandlerFunc(http.MethodGet, "/v1/playlists", app.listPlaylistsHandler)
+	router.HandlerFunc(http.MethodPost, "/v1/playlists", app.addPlaylistHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/playlists/:id", app.showPlaylistHandler)
+	router.HandlerFunc(http.MethodPatch, "/v1/playlists/:id", app.updatePlaylistHandler)
+	router.HandlerFunc(http.MethodDelete, "/v1/playlists/:id", app.deletePlaylistHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/playlists/:id/items", app.listPlaylistItemsHandler)
+	router.HandlerFunc(http.MethodPost, "/v1/playlists/:id/items", app.addPlaylistItemHandler)
+	router.HandlerFunc(http.MethodPatch, "/v1/playlists/:id/items/:position", app.movePlaylistItemHandler)
+	router.HandlerFunc(http.MethodDelete, "/v1/playlists/:id/items/:position", app.removePlaylistItemHandler)
 
 	router.Handler(http.MethodGet, "/swagger/*filepath", httpSwagger.WrapHandler)
 
-	return router
+	return app.requestID(router)
 }
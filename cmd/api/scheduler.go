@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"test_task/internal/provider"
+	"time"
+)
+
+// startResync periodically re-fetches the details of songs that haven't been
+// refreshed for longer than the configured age, until ctx is cancelled.
+func (app *application) startResync(ctx context.Context) {
+	if app.config.resync.interval <= 0 {
+		return
+	}
+
+	app.wg.Add(1)
+
+	go func() {
+		defer app.wg.Done()
+
+		ticker := time.NewTicker(app.config.resync.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				app.resyncStaleSongs(ctx)
+			}
+		}
+	}()
+}
+
+func (app *application) resyncStaleSongs(ctx context.Context) {
+	started := time.Now()
+
+	songs, err := app.models.Songs.GetStale(app.config.resync.maxAge, app.config.resync.batch)
+	if err != nil {
+		app.logger.Error("failed to get stale songs", slog.Any("error", err))
+		return
+	}
+
+	var changed, unchanged, failed int
+	fields := make(map[string]int)
+
+	for _, song := range songs {
+		if ctx.Err() != nil {
+			break
+		}
+
+		refreshCtx, cancel := context.WithTimeout(ctx, refreshTimeout)
+		changes, err := app.refreshSong(refreshCtx, song, true, resyncActor)
+		cancel()
+
+		switch {
+		case err != nil:
+			failed++
+			app.logger.Warn("failed to resync song",
+				slog.Int64("id", song.ID),
+				slog.Any("error", err))
+
+			// Only failures of the song itself put it off; a provider that is
+			// throttled or down, or a shutdown, says nothing about the song.
+			if ctx.Err() != nil || errors.Is(err, provider.ErrThrottled) || errors.Is(err, provider.ErrCircuitOpen) {
+				continue
+			}
+
+			err = app.models.Songs.SetResyncFailed(song.ID, app.config.resync.backoff)
+			if err != nil {
+				app.logger.Error("failed to record song resync failure", slog.Any("error", err))
+			}
+		case len(changes) == 0:
+			unchanged++
+		default:
+			changed++
+			for _, change := range changes {
+				fields[change.Field]++
+			}
+		}
+	}
+
+	app.logger.Info("stale songs resynced",
+		slog.Int("checked", len(songs)),
+		slog.Int("changed", changed),
+		slog.Int("unchanged", unchanged),
+		slog.Int("failed", failed),
+		slog.Any("changed_fields", fields),
+		slog.Duration("took", time.Since(started)))
+}
+
+// startTrashPurge periodically deletes the songs that have been in the trash
+// for longer than the configured retention, until ctx is cancelled.
+func (app *application) startTrashPurge(ctx context.Context) {
+	if app.config.trash.purgeInterval <= 0 {
+		return
+	}
+
+	app.wg.Add(1)
+
+	go func() {
+		defer app.wg.Done()
+
+		ticker := time.NewTicker(app.config.trash.purgeInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				app.purgeTrash()
+			}
+		}
+	}()
+}
+
+func (app *application) purgeTrash() {
+	purged, err := app.models.Songs.As(purgeActor).Purge(app.config.trash.retention)
+	if err != nil {
+		app.logger.Error("failed to purge trash", slog.Any("error", err))
+		return
+	}
+
+	app.logger.Info("trash purged",
+		slog.Int64("purged", purged),
+		slog.Duration("retention", app.config.trash.retention))
+}
@@ -21,6 +21,13 @@ func (app *application) serve() error {
 
 	shutdownError := make(chan error)
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	app.startEnrichmentWorkers(ctx)
+	app.startResync(ctx)
+	app.startTrashPurge(ctx)
+
 	go func() {
 		quit := make(chan os.Signal, 1)
 
@@ -31,14 +38,16 @@ func (app *application) serve() error {
 		app.logger.Info("shutting down server", slog.String(
 			"signal", s.String()))
 
-		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
-		defer cancel()
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 20*time.Second)
+		defer shutdownCancel()
 
-		err := srv.Shutdown(ctx)
+		err := srv.Shutdown(shutdownCtx)
 		if err != nil {
 			shutdownError <- err
 		}
 
+		cancel()
+
 		app.logger.Info("completing background task", slog.String(
 			"addr", srv.Addr))
 
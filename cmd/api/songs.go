@@ -1,9 +1,13 @@
 package main
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"strconv"
 	"test_task/internal/data"
 	"test_task/internal/validator"
@@ -12,76 +16,77 @@ import (
 )
 
 // @Summary Add a new song
-// @Description Adds a new song with its details fetched from an external API
+// @Description Adds a new song and fetches its details from an external API in the background
 // @Tags Songs
 // @Accept json
 // @Produce json
-// @Param song body object{song=string,group=string} true "Song and Group"
-// @Success 201 {object} map[string]string "Song added successfully"
+// @Param song body object{song=string,group=string,credits=[]data.Credit} true "Song, group and optional credited groups (role: feat, producer or composer)"
+// @Success 202 {object} envelope{song=data.Song} "Song accepted, details are being fetched"
 // @Failure 400 {object} map[string]string "Invalid request"
 // @Failure 422 {object} map[string]string "Validation errors"
 // @Failure 500 {object} map[string]string "the server encountered a problem and could not process your request"
 // @Router /v1/song [post]
 func (app *application) addSongHandler(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		Song  string `json:"song"`
-		Group string `json:"group"`
+		Song    string        `json:"song"`
+		Group   string        `json:"group"`
+		Credits []data.Credit `json:"credits"`
 	}
 
-	log := app.logger.With(
-		slog.String("group", req.Group),
-		slog.String("song", req.Song))
-
-	log.Info("attempting to add a new song")
-
 	err := app.readJSON(w, r, &req)
 	if err != nil {
 		app.badRequestResponse(w, r, err)
-		app.logger.Warn("bad request", err)
+		app.logger.Warn("bad request", slog.Any("error", err))
 		return
 	}
 
-	songDetail, err := app.fetchSongDetail(req.Song, req.Group)
-	if err != nil {
-		app.serverErrorResponse(w, r, err)
-		app.logger.Error("failed to fetch song details")
-		return
-	}
+	log := app.logger.With(
+		slog.String("group", req.Group),
+		slog.String("song", req.Song))
+
+	log.Info("attempting to add a new song")
 
 	song := &data.Song{
-		Song:    req.Song,
-		Group:   req.Group,
-		Release: songDetail.Release,
-		Text:    songDetail.Text,
-		Link:    songDetail.Link,
+		Song:             req.Song,
+		Group:            data.NormaliseGroupName(req.Group),
+		Credits:          req.Credits,
+		EnrichmentStatus: data.EnrichmentPending,
 	}
 
 	v := validator.New()
 
 	if data.ValidateSong(v, song); !v.Valid() {
 		app.failedValidationResponse(w, r, v.Errors)
-		app.logger.Warn("validation has not passed", err)
+		app.logger.Warn("validation has not passed", slog.Any("error", err))
 		return
 	}
 
-	err = app.models.Songs.Insert(song)
+	err = app.models.Songs.As(app.actor(r)).Insert(song)
 	if err != nil {
 		if errors.Is(err, data.ErrAlreadyExists) {
 			v.AddError("song", "a song of this group is already exists")
 			app.failedValidationResponse(w, r, v.Errors)
-			app.logger.Warn("song is already in database", err)
+			app.logger.Warn("song is already in database", slog.Any("error", err))
 			return
 		}
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to insert song", slog.Any("error", err))
+		return
 	}
 
-	err = app.writeJSON(w, http.StatusCreated, envelope{"song": song}, nil)
+	app.wakeEnrichmentWorkers()
+
+	headers := make(http.Header)
+	headers.Set("Location", fmt.Sprintf("/v1/song/%d", song.ID))
+
+	err = app.writeJSON(w, http.StatusAccepted, envelope{"song": song}, headers)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 		app.logger.Error("failed to add song")
 		return
 	}
 
-	log.Info("song added successfully")
+	log.Info("song accepted for enrichment")
 }
 
 // @Summary Update a song
@@ -90,11 +95,14 @@ func (app *application) addSongHandler(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Song ID"
-// @Param song body object{song=string,group=string} false "Updated song and group (optional)"
+// @Param If-Match header string false "ETag of the song version being edited"
+// @Param song body object{song=string,group=string,text=string} false "Updated song, group and lyrics (optional)"
 // @Success 200 {object} map[string]string "Song updated successfully"
+// @Header 200 {string} ETag "Version of the updated song"
 // @Failure 400 {object} map[string]string "Invalid request"
 // @Failure 404 {object} map[string]string "Song not found"
 // @Failure 409 {object} map[string]string "Edit conflict or duplicate song"
+// @Failure 412 {object} map[string]string "The song has changed since the given ETag"
 // @Failure 422 {object} map[string]string "Validation errors"
 // @Failure 500 {object} map[string]string "the server encountered a problem and could not process your request"
 // @Router /v1/song/{id} [patch]
@@ -115,23 +123,30 @@ func (app *application) updateSongHandler(w http.ResponseWriter, r *http.Request
 		switch {
 		case errors.Is(err, data.ErrNoRecordFound):
 			app.notFoundResponse(w, r)
-			app.logger.Warn("song not found", err)
+			app.logger.Warn("song not found", slog.Any("error", err))
 		default:
 			app.serverErrorResponse(w, r, err)
-			app.logger.Error("error updating song", err)
+			app.logger.Error("error updating song", slog.Any("error", err))
 		}
 		return
 	}
 
+	if !app.ifMatch(r, song) {
+		app.preconditionFailedResponse(w, r)
+		app.logger.Warn("song version does not match If-Match")
+		return
+	}
+
 	var req struct {
 		Song  *string `json:"song"`
 		Group *string `json:"group"`
+		Text  *string `json:"text"`
 	}
 
 	err = app.readJSON(w, r, &req)
 	if err != nil {
 		app.badRequestResponse(w, r, err)
-		log.Error("bad request", err)
+		log.Error("bad request", slog.Any("error", err))
 		return
 	}
 
@@ -140,38 +155,48 @@ func (app *application) updateSongHandler(w http.ResponseWriter, r *http.Request
 	}
 
 	if req.Group != nil {
-		song.Group = *req.Group
+		song.Group = data.NormaliseGroupName(*req.Group)
+	}
+
+	if req.Text != nil {
+		song.Text = *req.Text
 	}
 
 	v := validator.New()
 
 	if data.ValidateSong(v, song); !v.Valid() {
 		app.failedValidationResponse(w, r, v.Errors)
-		app.logger.Warn("validation has not passed", err)
+		app.logger.Warn("validation has not passed", slog.Any("error", err))
 		return
 	}
 
-	err = app.models.Songs.Update(song)
+	err = app.models.Songs.As(app.actor(r)).Update(song)
 	if err != nil {
 		switch {
+		case errors.Is(err, data.ErrEditConflict) && r.Header.Get("If-Match") != "":
+			app.preconditionFailedResponse(w, r)
+			app.logger.Warn("edit conflict error", slog.Any("error", err))
 		case errors.Is(err, data.ErrEditConflict):
 			app.editConflictResponse(w, r)
-			app.logger.Warn("edit conflict error", err)
+			app.logger.Warn("edit conflict error", slog.Any("error", err))
 		case errors.Is(err, data.ErrAlreadyExists):
 			v.AddError("song", "a song of this group is already exists")
 			app.failedValidationResponse(w, r, v.Errors)
-			app.logger.Warn("song is already in database", err)
+			app.logger.Warn("song is already in database", slog.Any("error", err))
 		default:
 			app.serverErrorResponse(w, r, err)
-			app.logger.Error("error updating song", err)
+			app.logger.Error("error updating song", slog.Any("error", err))
 		}
 		return
 	}
 
-	err = app.writeJSON(w, http.StatusOK, envelope{"song": song}, nil)
+	headers := make(http.Header)
+	headers.Set("ETag", songETag(song))
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"song": song}, headers)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
-		app.logger.Error("error updating song", err)
+		app.logger.Error("error updating song", slog.Any("error", err))
 	}
 
 	log.Info("song was edited successfully")
@@ -179,13 +204,16 @@ func (app *application) updateSongHandler(w http.ResponseWriter, r *http.Request
 }
 
 // @Summary Delete a song
-// @Description Deletes a song by its ID
+// @Description Moves a song to the trash, from where it can be restored until it is purged
 // @Tags Songs
 // @Accept json
 // @Produce json
 // @Param id path int true "Song ID"
+// @Param If-Match header string false "ETag of the song version being deleted"
 // @Success 200 {object} map[string]string "Song successfully deleted"
 // @Failure 404 {object} map[string]string "Song not found"
+// @Failure 409 {object} map[string]string "Edit conflict"
+// @Failure 412 {object} map[string]string "The song has changed since the given ETag"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /v1/song/{id} [delete]
 func (app *application) deleteSongHandler(w http.ResponseWriter, r *http.Request) {
@@ -200,15 +228,37 @@ func (app *application) deleteSongHandler(w http.ResponseWriter, r *http.Request
 
 	log.Info("trying to delete the song")
 
-	err = app.models.Songs.Delete(id)
+	song, err := app.models.Songs.Get(id)
 	if err != nil {
 		switch {
 		case errors.Is(err, data.ErrNoRecordFound):
 			app.notFoundResponse(w, r)
-			app.logger.Warn("song not found", err)
+			app.logger.Warn("song not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("error deleting song", slog.Any("error", err))
+		}
+		return
+	}
+
+	if !app.ifMatch(r, song) {
+		app.preconditionFailedResponse(w, r)
+		app.logger.Warn("song version does not match If-Match")
+		return
+	}
+
+	err = app.models.Songs.As(app.actor(r)).Delete(id, song.Version)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrEditConflict) && r.Header.Get("If-Match") != "":
+			app.preconditionFailedResponse(w, r)
+			app.logger.Warn("edit conflict error", slog.Any("error", err))
+		case errors.Is(err, data.ErrEditConflict):
+			app.editConflictResponse(w, r)
+			app.logger.Warn("edit conflict error", slog.Any("error", err))
 		default:
 			app.serverErrorResponse(w, r, err)
-			app.logger.Error("error deleting song", err)
+			app.logger.Error("error deleting song", slog.Any("error", err))
 		}
 		return
 	}
@@ -216,57 +266,143 @@ func (app *application) deleteSongHandler(w http.ResponseWriter, r *http.Request
 	err = app.writeJSON(w, http.StatusOK, envelope{"message": "song successfully deleted"}, nil)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
-		app.logger.Error("error deleting song", err)
+		app.logger.Error("error deleting song", slog.Any("error", err))
 	}
 
 	log.Info("song successfully deleted")
 }
 
+// @Summary Restore a deleted song
+// @Description Takes a song out of the trash
+// @Tags Songs
+// @Accept json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Success 200 {object} envelope{song=data.Song} "Restored song"
+// @Failure 404 {object} map[string]string "Song not found in the trash"
+// @Failure 409 {object} map[string]string "The song's place was taken while it was in the trash"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/song/{id}/restore [post]
+func (app *application) restoreSongHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	log := app.logger.With(
+		slog.String("song", strconv.FormatInt(id, 10)))
+
+	log.Info("trying to restore the song")
+
+	err = app.models.Songs.As(app.actor(r)).Restore(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("song not found in trash", slog.Any("error", err))
+		case errors.Is(err, data.ErrAlreadyExists):
+			app.conflictResponse(w, r, "a song of this group with the same name already exists")
+			app.logger.Warn("song is already in database", slog.Any("error", err))
+		case errors.Is(err, data.ErrTrackTaken):
+			app.conflictResponse(w, r, "the song's album track number is already taken")
+			app.logger.Warn("album track is taken", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("error restoring song", slog.Any("error", err))
+		}
+		return
+	}
+
+	app.wakeEnrichmentWorkers()
+
+	song, err := app.models.Songs.Get(id)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to get song", slog.Any("error", err))
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"song": song}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("error restoring song", slog.Any("error", err))
+	}
+
+	log.Info("song successfully restored")
+}
+
+// @Summary List deleted songs
+// @Description Lists the songs in the trash, most recently deleted first
+// @Tags Songs
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Number of items per page" default(5)
+// @Success 200 {object} envelope{songs=[]data.Song,metadata=data.Metadata} "Deleted songs"
+// @Failure 422 {object} map[string]string "Validation errors"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/trash [get]
+func (app *application) listTrashHandler(w http.ResponseWriter, r *http.Request) {
+	var filters data.Filters
+
+	v := validator.New()
+	qs := r.URL.Query()
+
+	filters.Page = app.readInt(qs, "page", 1, v)
+	filters.PageSize = app.readInt(qs, "page_size", 5, v)
+
+	if data.ValidatePagination(v, filters); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		app.logger.Warn("validation has not passed")
+		return
+	}
+
+	songs, metadata, err := app.models.Songs.GetTrash(filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to get trash", slog.Any("error", err))
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"songs": songs, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to get trash", slog.Any("error", err))
+	}
+}
+
 // @Summary List songs with filters
 // @Description Lists all songs with optional filters and pagination
 // @Tags Songs
 // @Accept json
 // @Produce json
 // @Param song query string false "Song name"
-// @Param group query string false "Group name"
+// @Param group query string false "Group name, matching the primary group or any credited group"
 // @Param releaseDate query string false "Release date"
 // @Param text query string false "Text"
 // @Param link query string false "Link"
+// @Param album query string false "Album title"
+// @Param released_from query string false "Released on or after (YYYY-MM-DD)"
+// @Param released_to query string false "Released on or before (YYYY-MM-DD)"
+// @Param year query int false "Release year"
+// @Param tag query string false "Tags, comma-separated"
+// @Param genre query string false "Genres, comma-separated"
+// @Param tag_mode query string false "Whether a song needs all (and) or any (or) of the given tags and genres" default(or) Enums(and,or)
+// @Param platform query string false "Platforms the song must have links on, comma-separated (youtube, spotify, apple_music, bandcamp)"
 // @Param page query int false "Page number" default(1)
 // @Param page_size query int false "Number of items per page" default(5)
-// @Param sort query string false "Sort by field" default(id) Enum(id,song,group,release,text,link,-id,-song,-group,-release,-text,-link)
+// @Param sort query string false "Sort by field" default(id) Enum(id,song,group,release,text,link,album,-id,-song,-group,-release,-text,-link,-album)
 // @Success 200 {object} envelope{songs=[]data.Song,metadata=data.Metadata} "List of songs"
 // @Failure 400 {object} map[string]string "Bad request"
 // @Failure 500 {object} map[string]string "the server encountered a problem and could not process your request"
 // @Router /v1/songs [get]
 func (app *application) listSongsHandler(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		Song    string `json:"song"`
-		Group   string `json:"group"`
-		Release string `json:"releaseDate"`
-		Text    string `json:"text"`
-		Link    string `json:"link"`
-		data.Filters
-	}
-
 	v := validator.New()
 
-	qs := r.URL.Query()
-
-	req.Song = app.readString(qs, "song", "")
-	req.Group = app.readString(qs, "group", "")
-	req.Release = app.readString(qs, "releaseDate", "")
-	req.Text = app.readString(qs, "text", "")
-	req.Link = app.readString(qs, "link", "")
+	req := app.readSongFilter(r.URL.Query(), v)
 
-	req.Filters.Page = app.readInt(qs, "page", 1, v)
-	req.Filters.PageSize = app.readInt(qs, "page_size", 5, v)
-
-	req.Filters.Sort = app.readString(qs, "sort", "id")
-
-	req.Filters.SortSafelist = []string{"id", "song", "group", "release", "text", "link", "-id", "-song", "-group", "-release", "-text", "-link"}
-
-	if data.ValidateFilters(v, req.Filters); !v.Valid() {
+	if data.ValidateSongFilter(v, req); !v.Valid() {
 		app.failedValidationResponse(w, r, v.Errors)
 		app.logger.Warn("validation has not passed")
 		return
@@ -279,37 +415,77 @@ func (app *application) listSongsHandler(w http.ResponseWriter, r *http.Request)
 
 	log.Info("trying to list songs")
 
-	songs, metadata, err := app.models.Songs.GetAll(req.Song, req.Group, req.Release, req.Text, req.Link, req.Filters)
+	songs, metadata, err := app.models.Songs.GetAll(req)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
-		app.logger.Error("error getting songs", err)
+		app.logger.Error("error getting songs", slog.Any("error", err))
 		return
 	}
 
 	err = app.writeJSON(w, http.StatusOK, envelope{"songs": songs, "metadata": metadata}, nil)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
-		app.logger.Error("error getting songs", err)
+		app.logger.Error("error getting songs", slog.Any("error", err))
 	}
 
 	log.Info("listed successfully")
 }
 
+func (app *application) readSongFilter(qs url.Values, v *validator.Validator) data.SongFilter {
+	var filter data.SongFilter
+
+	filter.Song = app.readString(qs, "song", "")
+	filter.Group = app.readString(qs, "group", "")
+	filter.Release = app.readString(qs, "releaseDate", "")
+	filter.Text = app.readString(qs, "text", "")
+	filter.Link = app.readString(qs, "link", "")
+	filter.Album = app.readString(qs, "album", "")
+
+	filter.ReleasedFrom = app.readDate(qs, "released_from", v)
+	filter.ReleasedTo = app.readDate(qs, "released_to", v)
+	filter.Year = app.readInt(qs, "year", 0, v)
+
+	filter.Tags = app.readCSV(qs, "tag", nil)
+	filter.Genres = app.readCSV(qs, "genre", nil)
+	filter.TagMode = app.readString(qs, "tag_mode", data.TagMatchAny)
+
+	filter.Platforms = app.readCSV(qs, "platform", nil)
+
+	filter.Filters.Page = app.readInt(qs, "page", 1, v)
+	filter.Filters.PageSize = app.readInt(qs, "page_size", 5, v)
+
+	filter.Filters.Sort = app.readString(qs, "sort", "id")
+
+	filter.Filters.SortSafelist = []string{"id", "song", "group", "release", "text", "link", "album", "-id", "-song", "-group", "-release", "-text", "-link", "-album"}
+
+	return filter
+}
+
 // @Summary Get paginated lyrics of a song
-// @Description Retrieves the lyrics of a song with pagination by verse
+// @Description Retrieves the lyrics of a song, or their translation into lang, paginated by stanza (verses separated by blank lines) or by line, with line offsets if the lyrics are synced. In parallel mode original and translated stanzas come in pairs. Synced lyrics can also be fetched whole as an LRC file
 // @Tags Songs
 // @Accept json
-// @Produce json
+// @Produce json,plain
 // @Param id path int true "Song ID"
+// @Param mode query string false "Pagination unit" default(stanza) Enums(stanza,line)
+// @Param lang query string false "BCP-47 language of a translation"
+// @Param parallel query bool false "Pair original and translated stanzas; requires lang and stanza mode" default(false)
+// @Param format query string false "Response format; LRC can also be asked for with Accept: application/lrc or text/plain" default(json) Enums(json,lrc)
 // @Param page query int false "Page number" default(1)
-// @Param page_size query int false "Number of verses per page" default(1)
-// @Success 200 {object} envelope{lyrics=[]string,metadata=data.Metadata} "Paginated song lyrics"
+// @Param page_size query int false "Number of stanzas or lines per page" default(1)
+// @Success 200 {object} envelope{lyrics=[]data.Verse,metadata=data.Metadata} "Paginated song lyrics; []data.Line in line mode, []data.VersePair in parallel mode"
 // @Failure 400 {object} map[string]string "Bad request"
-// @Failure 404 {object} map[string]string "Song not found"
+// @Failure 404 {object} map[string]string "Song or translation not found"
+// @Failure 406 {object} map[string]string "LRC was asked for but the lyrics are not synced"
+// @Failure 422 {object} map[string]string "Validation errors"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /v1/song/{id}/lyrics [get]
 func (app *application) showLyricsHandler(w http.ResponseWriter, r *http.Request) {
 	var req struct {
+		Mode     string
+		Format   string
+		Lang     string
+		Parallel bool
 		data.Filters
 	}
 
@@ -322,37 +498,206 @@ func (app *application) showLyricsHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	req.Mode = app.readString(qs, "mode", data.LyricsByStanza)
+	req.Format = app.readString(qs, "format", "json")
+	req.Lang = app.readString(qs, "lang", "")
+	req.Parallel = app.readBool(qs, "parallel", false, v)
 	req.Filters.Page = app.readInt(qs, "page", 1, v)
 	req.Filters.PageSize = app.readInt(qs, "page_size", 1, v)
 
+	v.Check(validator.PermittedValue(req.Mode, data.LyricsByStanza, data.LyricsByLine), "mode", "must be stanza or line")
+	v.Check(validator.PermittedValue(req.Format, "json", "lrc"), "format", "must be json or lrc")
+
+	mediaType := lrcMediaType(r)
+	if mediaType != "" {
+		v.Check(req.Lang == "", "lang", "translations are not available as LRC")
+	}
+
+	if req.Lang != "" {
+		lang, ok := data.CanonicalLanguageTag(req.Lang)
+		v.Check(ok, "lang", "must be a BCP-47 language tag of at most 35 characters")
+		req.Lang = lang
+	}
+
+	if req.Parallel {
+		v.Check(req.Lang != "", "lang", "must be provided in parallel mode")
+		v.Check(req.Mode == data.LyricsByStanza, "mode", "must be stanza in parallel mode")
+	}
+
+	if data.ValidatePagination(v, req.Filters); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		app.logger.Warn("validation has not passed")
+		return
+	}
+
 	log := app.logger.With(
 		slog.String("song", strconv.FormatInt(id, 10)))
 
 	log.Info("attempting to get song's lyrics")
 
-	lyrics, metadata, err := app.models.Songs.GetLyrics(id, req.Page, req.PageSize)
+	text, timings, err := app.models.Songs.GetTimedLyrics(id)
 
 	if err != nil {
 		switch {
 		case errors.Is(err, data.ErrNoRecordFound):
 			app.notFoundResponse(w, r)
-			app.logger.Warn("song not found", err)
+			app.logger.Warn("song not found", slog.Any("error", err))
 		default:
 			app.serverErrorResponse(w, r, err)
-			app.logger.Error("failed to get song", err)
+			app.logger.Error("failed to get song", slog.Any("error", err))
 		}
 		return
 	}
 
-	err = app.writeJSON(w, http.StatusOK, envelope{"lyrics": lyrics, "metadata": metadata}, nil)
+	if mediaType != "" {
+		app.writeLRC(w, r, id, mediaType, text, timings)
+		return
+	}
+
+	var translation *data.LyricTranslation
+
+	if req.Lang != "" {
+		translation, err = app.models.Translations.Get(id, req.Lang)
+		if err != nil {
+			switch {
+			case errors.Is(err, data.ErrNoRecordFound):
+				app.notFoundResponse(w, r)
+				app.logger.Warn("lyrics translation not found", slog.Any("error", err))
+			default:
+				app.serverErrorResponse(w, r, err)
+				app.logger.Error("failed to get lyrics translation", slog.Any("error", err))
+			}
+			return
+		}
+	}
+
+	var lyrics any
+	var metadata data.Metadata
+
+	switch {
+	case req.Parallel:
+		pairs := data.PairVerses(data.SplitVerses(text), data.SplitVerses(translation.Text))
+		lyrics, metadata = data.Paginate(pairs, req.Filters)
+	case translation != nil && req.Mode == data.LyricsByLine:
+		lyrics, metadata = data.Paginate(data.SplitLines(translation.Text), req.Filters)
+	case translation != nil:
+		lyrics, metadata = data.Paginate(data.SplitVerses(translation.Text), req.Filters)
+	case req.Mode == data.LyricsByLine:
+		lines := data.SplitLines(text)
+		timings.ApplyToLines(lines)
+		lyrics, metadata = data.Paginate(lines, req.Filters)
+	default:
+		verses := data.SplitVerses(text)
+		timings.ApplyToVerses(verses)
+		lyrics, metadata = data.Paginate(verses, req.Filters)
+	}
+
+	env := envelope{"lyrics": lyrics, "mode": req.Mode, "metadata": metadata}
+	if translation != nil {
+		env["lang"] = translation.Language
+		env["aligned"] = translation.Aligned
+	}
+
+	err = app.writeJSON(w, http.StatusOK, env, http.Header{"Vary": {"Accept"}})
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
-		app.logger.Error("failed to get song's lyrics", err)
+		app.logger.Error("failed to get song's lyrics", slog.Any("error", err))
 	}
 
 	log.Info("song's lyrics was gotten successfully")
 }
 
+// writeLRC sends the synced lyrics of a song as an LRC file.
+func (app *application) writeLRC(w http.ResponseWriter, r *http.Request, id int64, mediaType, text string, timings data.Timings) {
+	if len(timings) == 0 {
+		app.notAcceptableResponse(w, r, "the lyrics of this song are not synced, so they are only available as JSON")
+		app.logger.Warn("song's lyrics are not synced")
+		return
+	}
+
+	song, err := app.models.Songs.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("song not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to get song", slog.Any("error", err))
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", mediaType+"; charset=utf-8")
+	w.Header().Set("Vary", "Accept")
+	w.WriteHeader(http.StatusOK)
+
+	_, err = io.WriteString(w, data.FormatLRC(song.Song, song.Group, text, timings))
+	if err != nil {
+		app.logger.Error("failed to write song's lyrics", slog.Any("error", err))
+	}
+}
+
+// @Summary Upload synced lyrics
+// @Description Replaces the lyrics of a song with the lines of an LRC file and stores their timestamps, which must strictly increase. Changed lyrics are recorded as a new revision
+// @Tags Songs
+// @Accept plain
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param lrc body string true "Lyrics in the LRC format, e.g. [00:12.34]First line"
+// @Success 200 {object} envelope{song=data.Song} "Song with the uploaded lyrics"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Song not found"
+// @Failure 422 {object} map[string]string "Validation errors"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/song/{id}/lyrics [put]
+func (app *application) uploadLyricsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1_048_576))
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		app.logger.Warn("bad request", slog.Any("error", err))
+		return
+	}
+
+	v := validator.New()
+
+	text, timings, err := data.ParseLRC(string(body))
+	if err != nil {
+		v.AddError("lrc", err.Error())
+		app.failedValidationResponse(w, r, v.Errors)
+		app.logger.Warn("validation has not passed")
+		return
+	}
+
+	log := app.logger.With(
+		slog.String("song", strconv.FormatInt(id, 10)))
+
+	log.Info("attempting to upload synced lyrics")
+
+	err = app.models.Songs.As(app.actor(r)).SetTimedLyrics(id, text, timings)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("song not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to upload synced lyrics", slog.Any("error", err))
+		}
+		return
+	}
+
+	app.writeSong(w, r, id)
+
+	log.Info("synced lyrics uploaded successfully")
+}
+
 ///////////////////////////////////
 
 // @Summary Get a song by ID
@@ -361,7 +706,10 @@ func (app *application) showLyricsHandler(w http.ResponseWriter, r *http.Request
 // @Accept json
 // @Produce json
 // @Param id path int true "Song ID"
+// @Param include query string false "Extra data to include" Enums(provenance)
 // @Success 200 {object} envelope{song=data.Song} "Successfully retrieved song"
+// @Header 200 {string} ETag "Version of the song, for If-Match"
+// @Failure 422 {object} map[string]string "Validation errors"
 // @Failure 404 {object} map[string]string "The requested resource could not be found"
 // @Failure 500 {object} map[string]string "The server encountered a problem and could not process your request"
 // @Router /v1/song/{id} [get]
@@ -375,6 +723,19 @@ func (app *application) showSongHandler(w http.ResponseWriter, r *http.Request)
 	log := app.logger.With(
 		slog.String("song", strconv.FormatInt(id, 10)))
 
+	v := validator.New()
+
+	include := app.readCSV(r.URL.Query(), "include", nil)
+	for _, value := range include {
+		v.Check(validator.PermittedValue(value, "provenance"), "include", "invalid include value")
+	}
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		app.logger.Warn("validation has not passed")
+		return
+	}
+
 	log.Info("attempting to get song")
 
 	song, err := app.models.Songs.Get(id)
@@ -383,19 +744,214 @@ func (app *application) showSongHandler(w http.ResponseWriter, r *http.Request)
 		switch {
 		case errors.Is(err, data.ErrNoRecordFound):
 			app.notFoundResponse(w, r)
-			app.logger.Warn("song not found", err)
+			app.logger.Warn("song not found", slog.Any("error", err))
 		default:
 			app.serverErrorResponse(w, r, err)
-			app.logger.Error("failed to get song", err)
+			app.logger.Error("failed to get song", slog.Any("error", err))
 		}
 		return
 	}
 
-	err = app.writeJSON(w, http.StatusOK, envelope{"song": song}, nil)
+	if validator.PermittedValue("provenance", include...) {
+		song.Provenance, err = app.models.Songs.GetProvenance(song.ID)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to get song provenance", slog.Any("error", err))
+			return
+		}
+	}
+
+	headers := make(http.Header)
+	headers.Set("ETag", songETag(song))
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"song": song}, headers)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
-		app.logger.Error("failed to get song", err)
+		app.logger.Error("failed to get song", slog.Any("error", err))
 	}
 
 	log.Info("song was gotten successfully")
 }
+
+// @Summary Refresh song details
+// @Description Re-queries the details provider for a song and applies the changed release date, text and link
+// @Tags Songs
+// @Accept json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param dry_run query bool false "Only show what would change"
+// @Success 200 {object} envelope{song=data.Song,changes=[]data.DetailChange} "Refreshed song and applied changes"
+// @Failure 404 {object} map[string]string "Song not found"
+// @Failure 502 {object} map[string]string "The details provider failed"
+// @Failure 503 {object} map[string]string "The details provider is unavailable or throttled"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/song/{id}/refresh [post]
+func (app *application) refreshSongHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	dryRun := app.readString(r.URL.Query(), "dry_run", "false") == "true"
+
+	log := app.logger.With(
+		slog.String("song", strconv.FormatInt(id, 10)))
+
+	log.Info("attempting to refresh song details")
+
+	song, err := app.models.Songs.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("song not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to get song", slog.Any("error", err))
+		}
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), refreshTimeout)
+	defer cancel()
+
+	changes, err := app.refreshSong(ctx, song, !dryRun, app.actor(r))
+	if err != nil {
+		var fetchErr *fetchError
+
+		switch {
+		case errors.As(err, &fetchErr):
+			app.providerErrorResponse(w, r, err)
+			app.logger.Warn("failed to refresh song details", slog.Any("error", err))
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("song not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to save song details", slog.Any("error", err))
+		}
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"song": song, "changes": changes, "dry_run": dryRun}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to refresh song details", slog.Any("error", err))
+	}
+
+	log.Info("song details refreshed successfully", slog.Int("changes", len(changes)))
+}
+
+// @Summary Refresh details of many songs
+// @Description Re-queries the details provider for one page of songs selected by the same filters as GET /v1/songs. Each song reports its status: refreshed, failed, or not_processed when the batch ran out of time before reaching it
+// @Tags Songs
+// @Accept json
+// @Produce json
+// @Param song query string false "Song name"
+// @Param group query string false "Group name"
+// @Param releaseDate query string false "Release date"
+// @Param text query string false "Text"
+// @Param link query string false "Link"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Number of items per page" default(5)
+// @Param sort query string false "Sort by field" default(id)
+// @Param dry_run query bool false "Only show what would change"
+// @Success 200 {object} envelope{results=[]object,metadata=data.Metadata} "Per-song changes"
+// @Failure 422 {object} map[string]string "Validation errors"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/songs/refresh [post]
+func (app *application) refreshSongsHandler(w http.ResponseWriter, r *http.Request) {
+	v := validator.New()
+	qs := r.URL.Query()
+
+	req := app.readSongFilter(qs, v)
+	dryRun := app.readString(qs, "dry_run", "false") == "true"
+
+	if data.ValidateSongFilter(v, req); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		app.logger.Warn("validation has not passed")
+		return
+	}
+
+	log := app.logger.With(
+		slog.String("song filter", req.Song),
+		slog.String("group filter", req.Group),
+		slog.Int("page filter", req.Page))
+
+	log.Info("trying to refresh songs")
+
+	songs, metadata, err := app.models.Songs.GetAll(req)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("error getting songs", slog.Any("error", err))
+		return
+	}
+
+	type result struct {
+		ID      int64               `json:"id"`
+		Status  string              `json:"status"`
+		Changes []data.DetailChange `json:"changes"`
+		Error   string              `json:"error,omitempty"`
+	}
+
+	results := make([]result, 0, len(songs))
+
+	// Every song gets the full refresh timeout; once the batch has used up
+	// its own time, the remaining songs are reported as not processed.
+	batchCtx, cancelBatch := context.WithTimeout(r.Context(), refreshBatchTimeout)
+	defer cancelBatch()
+
+	for _, song := range songs {
+		if batchCtx.Err() != nil {
+			results = append(results, result{ID: song.ID, Status: refreshNotProcessed, Changes: []data.DetailChange{}})
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(batchCtx, refreshTimeout)
+		changes, err := app.refreshSong(ctx, song, !dryRun, app.actor(r))
+		cancel()
+
+		res := result{ID: song.ID, Status: refreshDone, Changes: changes}
+		if err != nil {
+			res.Status = refreshFailed
+			res.Error = err.Error()
+		}
+
+		results = append(results, res)
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"results": results, "metadata": metadata, "dry_run": dryRun}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("error refreshing songs", slog.Any("error", err))
+	}
+
+	log.Info("songs refreshed", slog.Int("songs", len(results)))
+}
+
+// writeSong responds with the current state of the song id and its ETag,
+// for handlers that change what belongs to a song rather than the song
+// itself.
+func (app *application) writeSong(w http.ResponseWriter, r *http.Request, id int64) {
+	song, err := app.models.Songs.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to get song", slog.Any("error", err))
+		}
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("ETag", songETag(song))
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"song": song}, headers)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to get song", slog.Any("error", err))
+	}
+}
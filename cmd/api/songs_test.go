@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"test_task/internal/data"
+	"test_task/internal/provider"
+	"test_task/internal/testdb"
+	"testing"
+)
+
+func TestAddSongHandlerRejectsInvalidInput(t *testing.T) {
+	app := newTestApplication(t, nil)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"malformed JSON", `{"song": "Uprising"`, http.StatusBadRequest},
+		{"unknown field", `{"song": "Uprising", "group": "Muse", "year": 2009}`, http.StatusBadRequest},
+		{"missing group", `{"song": "Uprising"}`, http.StatusUnprocessableEntity},
+		{"missing song", `{"group": "Muse"}`, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/v1/song", strings.NewReader(tt.body))
+
+			app.routes().ServeHTTP(rr, r)
+
+			if rr.Code != tt.status {
+				t.Errorf("status = %d, want %d; body: %s", rr.Code, tt.status, rr.Body)
+			}
+		})
+	}
+}
+
+func TestAddSongHandlerEnrichesFromProvider(t *testing.T) {
+	app := newTestApplication(t, testdb.New(t), provider.Fixture{
+		Group: "Muse",
+		Song:  "Supermassive Black Hole",
+		SongDetail: data.SongDetail{
+			Release: "16.07.2006",
+			Text:    "Ooh baby, don't you know I suffer?\nOoh baby, can you hear me moan?",
+			Link:    "https://www.youtube.com/watch?v=Xsp3_a-PMTw",
+		},
+	})
+
+	rr := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/v1/song", strings.NewReader(`{"song": "Supermassive Black Hole", "group": "Muse"}`))
+
+	app.routes().ServeHTTP(rr, r)
+
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d; body: %s", rr.Code, http.StatusAccepted, rr.Body)
+	}
+
+	var added struct {
+		Song data.Song `json:"song"`
+	}
+
+	err := json.NewDecoder(rr.Body).Decode(&added)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if added.Song.EnrichmentStatus != data.EnrichmentPending {
+		t.Errorf("enrichment_status = %q, want %q", added.Song.EnrichmentStatus, data.EnrichmentPending)
+	}
+	if got := rr.Header().Get("Location"); got != "/v1/song/1" {
+		t.Errorf("Location = %q, want /v1/song/1", got)
+	}
+
+	runEnrichmentJobs(t, app)
+
+	song, err := app.models.Songs.Get(added.Song.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if song.EnrichmentStatus != data.EnrichmentDone {
+		t.Errorf("enrichment_status = %q, want %q (%s)", song.EnrichmentStatus, data.EnrichmentDone, song.EnrichmentError)
+	}
+	if song.Release != "16.07.2006" || song.Link != "https://www.youtube.com/watch?v=Xsp3_a-PMTw" {
+		t.Errorf("details = %q, %q", song.Release, song.Link)
+	}
+	if len(song.Links) != 1 || song.Links[0].Platform != data.PlatformYouTube {
+		t.Errorf("links = %+v", song.Links)
+	}
+}
+
+func TestAddSongHandlerMarksUnknownSongFailed(t *testing.T) {
+	app := newTestApplication(t, testdb.New(t))
+
+	rr := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/v1/song", strings.NewReader(`{"song": "Uprising", "group": "Muse"}`))
+
+	app.routes().ServeHTTP(rr, r)
+
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d; body: %s", rr.Code, http.StatusAccepted, rr.Body)
+	}
+
+	runEnrichmentJobs(t, app)
+
+	song, err := app.models.Songs.Get(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if song.EnrichmentStatus != data.EnrichmentFailed {
+		t.Errorf("enrichment_status = %q, want %q", song.EnrichmentStatus, data.EnrichmentFailed)
+	}
+}
+
+func TestAddSongHandlerRejectsDuplicate(t *testing.T) {
+	app := newTestApplication(t, testdb.New(t))
+
+	for i, want := range []int{http.StatusAccepted, http.StatusUnprocessableEntity} {
+		rr := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/v1/song", strings.NewReader(`{"song": "Uprising", "group": "Muse"}`))
+
+		app.routes().ServeHTTP(rr, r)
+
+		if rr.Code != want {
+			t.Errorf("request %d: status = %d, want %d; body: %s", i+1, rr.Code, want, rr.Body)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"test_task/internal/data"
+	"test_task/internal/validator"
+)
+
+// @Summary Tag a song
+// @Description Attaches a free-form tag to a song, creating the tag if needed
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param name path string true "Tag name"
+// @Success 200 {object} envelope{song=data.Song} "Tagged song"
+// @Failure 404 {object} map[string]string "Song not found"
+// @Failure 422 {object} map[string]string "Validation errors"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/song/{id}/tags/{name} [put]
+func (app *application) attachTagHandler(w http.ResponseWriter, r *http.Request) {
+	app.attachTag(w, r, data.KindTag)
+}
+
+// @Summary Untag a song
+// @Description Detaches a free-form tag from a song
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param name path string true "Tag name"
+// @Success 200 {object} envelope{song=data.Song} "Untagged song"
+// @Failure 404 {object} map[string]string "Song or tag not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/song/{id}/tags/{name} [delete]
+func (app *application) detachTagHandler(w http.ResponseWriter, r *http.Request) {
+	app.detachTag(w, r, data.KindTag)
+}
+
+// @Summary Add a genre to a song
+// @Description Attaches a genre to a song, creating the genre if needed
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param name path string true "Genre name"
+// @Success 200 {object} envelope{song=data.Song} "Song with the genre"
+// @Failure 404 {object} map[string]string "Song not found"
+// @Failure 422 {object} map[string]string "Validation errors"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/song/{id}/genres/{name} [put]
+func (app *application) attachGenreHandler(w http.ResponseWriter, r *http.Request) {
+	app.attachTag(w, r, data.KindGenre)
+}
+
+// @Summary Remove a genre from a song
+// @Description Detaches a genre from a song
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param name path string true "Genre name"
+// @Success 200 {object} envelope{song=data.Song} "Song without the genre"
+// @Failure 404 {object} map[string]string "Song or genre not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/song/{id}/genres/{name} [delete]
+func (app *application) detachGenreHandler(w http.ResponseWriter, r *http.Request) {
+	app.detachTag(w, r, data.KindGenre)
+}
+
+// @Summary List tags and genres
+// @Description Lists tags and genres with the number of songs they are attached to, most used first
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param kind query string false "Only tags or only genres" Enums(tag,genre)
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Number of items per page" default(20)
+// @Success 200 {object} envelope{tags=[]data.Tag,metadata=data.Metadata} "Tags with usage counts"
+// @Failure 422 {object} map[string]string "Validation errors"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/tags [get]
+func (app *application) listTagsHandler(w http.ResponseWriter, r *http.Request) {
+	var filters data.Filters
+
+	v := validator.New()
+	qs := r.URL.Query()
+
+	kind := app.readString(qs, "kind", "")
+	filters.Page = app.readInt(qs, "page", 1, v)
+	filters.PageSize = app.readInt(qs, "page_size", 20, v)
+
+	v.Check(kind == "" || validator.PermittedValue(kind, data.KindTag, data.KindGenre), "kind", "must be tag or genre")
+
+	if data.ValidatePagination(v, filters); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		app.logger.Warn("validation has not passed")
+		return
+	}
+
+	tags, metadata, err := app.models.Tags.GetAll(kind, filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to get tags", slog.Any("error", err))
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"tags": tags, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to get tags", slog.Any("error", err))
+	}
+}
+
+func (app *application) attachTag(w http.ResponseWriter, r *http.Request, kind string) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	tag := &data.Tag{
+		Kind: kind,
+		Name: data.NormaliseTagName(httprouter.ParamsFromContext(r.Context()).ByName("name")),
+	}
+
+	v := validator.New()
+
+	if data.ValidateTag(v, tag); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		app.logger.Warn("validation has not passed")
+		return
+	}
+
+	log := app.logger.With(
+		slog.String("song", strconv.FormatInt(id, 10)),
+		slog.String(kind, tag.Name))
+
+	log.Info("attempting to attach " + kind)
+
+	err = app.models.Tags.As(app.actor(r)).Attach(id, kind, tag.Name)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("song not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to attach "+kind, slog.Any("error", err))
+		}
+		return
+	}
+
+	app.writeSong(w, r, id)
+
+	log.Info(kind + " attached successfully")
+}
+
+func (app *application) detachTag(w http.ResponseWriter, r *http.Request, kind string) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	name := httprouter.ParamsFromContext(r.Context()).ByName("name")
+
+	log := app.logger.With(
+		slog.String("song", strconv.FormatInt(id, 10)),
+		slog.String(kind, name))
+
+	log.Info("attempting to detach " + kind)
+
+	err = app.models.Tags.As(app.actor(r)).Detach(id, kind, name)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn(kind+" is not attached to the song", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to detach "+kind, slog.Any("error", err))
+		}
+		return
+	}
+
+	app.writeSong(w, r, id)
+
+	log.Info(kind + " detached successfully")
+}
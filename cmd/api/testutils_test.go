@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"log/slog"
+	"test_task/internal/data"
+	"test_task/internal/provider"
+	"testing"
+	"time"
+)
+
+// newTestApplication returns an application whose song details come from
+// fixtures instead of the network. Without a database its models must not be
+// used; see testdb.New.
+func newTestApplication(t *testing.T, db *sql.DB, fixtures ...provider.Fixture) *application {
+	t.Helper()
+
+	app := &application{
+		logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+		models:   data.NewModels(db),
+		provider: provider.NewFake(fixtures...),
+
+		enrichmentWake: make(chan struct{}, 1),
+	}
+
+	app.config.enrichment.maxAttempts = 3
+	app.config.enrichment.lease = time.Minute
+
+	return app
+}
+
+// runEnrichmentJobs processes the queued enrichment jobs the way a worker
+// would, until none are left to lease.
+func runEnrichmentJobs(t *testing.T, app *application) {
+	t.Helper()
+
+	for {
+		jobs, err := app.models.EnrichmentJobs.Lease(app.config.enrichment.lease, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(jobs) == 0 {
+			return
+		}
+
+		for _, job := range jobs {
+			app.processEnrichmentJob(context.Background(), job)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"test_task/internal/data"
+	"test_task/internal/validator"
+)
+
+// @Summary List lyrics translations
+// @Description Lists the languages a song's lyrics are translated into, without the text. A translation is aligned while it has as many stanzas as the original
+// @Tags Lyrics
+// @Accept json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Success 200 {object} envelope{translations=[]data.LyricTranslation} "Lyrics translations"
+// @Failure 404 {object} map[string]string "Song not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/song/{id}/translations [get]
+func (app *application) listTranslationsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	_, err = app.models.Songs.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("song not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to get song", slog.Any("error", err))
+		}
+		return
+	}
+
+	translations, err := app.models.Translations.GetAll(id)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to get lyrics translations", slog.Any("error", err))
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"translations": translations}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to get lyrics translations", slog.Any("error", err))
+	}
+}
+
+// @Summary Set a lyrics translation
+// @Description Adds or replaces the translation of a song's lyrics into a BCP-47 language. The translation must have as many stanzas as the original lyrics
+// @Tags Lyrics
+// @Accept json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param lang path string true "BCP-47 language tag, e.g. en or pt-BR"
+// @Param translation body object{text=string} true "Translated lyrics, stanzas separated by blank lines"
+// @Success 200 {object} envelope{translation=data.LyricTranslation} "Lyrics translation"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Song not found"
+// @Failure 422 {object} map[string]string "Validation errors"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/song/{id}/translations/{lang} [put]
+func (app *application) setTranslationHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var req struct {
+		Text string `json:"text"`
+	}
+
+	err = app.readJSON(w, r, &req)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		app.logger.Warn("bad request", slog.Any("error", err))
+		return
+	}
+
+	original, err := app.models.Songs.GetLyrics(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("song not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to get song", slog.Any("error", err))
+		}
+		return
+	}
+
+	translation := &data.LyricTranslation{
+		Language: httprouter.ParamsFromContext(r.Context()).ByName("lang"),
+		Text:     req.Text,
+		Author:   app.actor(r).Name,
+	}
+
+	v := validator.New()
+
+	if data.ValidateTranslation(v, translation, original); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		app.logger.Warn("validation has not passed")
+		return
+	}
+
+	translation.Language, _ = data.CanonicalLanguageTag(translation.Language)
+	translation.Aligned = true
+
+	log := app.logger.With(
+		slog.String("song", strconv.FormatInt(id, 10)),
+		slog.String("lang", translation.Language))
+
+	log.Info("attempting to set lyrics translation")
+
+	err = app.models.Translations.As(app.actor(r)).Put(id, translation)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("song not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to set lyrics translation", slog.Any("error", err))
+		}
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"translation": translation}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to set lyrics translation", slog.Any("error", err))
+	}
+
+	log.Info("lyrics translation set successfully")
+}
+
+// @Summary Remove a lyrics translation
+// @Description Removes the translation of a song's lyrics into a language
+// @Tags Lyrics
+// @Accept json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param lang path string true "BCP-47 language tag"
+// @Success 200 {object} map[string]string "Translation removed"
+// @Failure 404 {object} map[string]string "Song or translation not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/song/{id}/translations/{lang} [delete]
+func (app *application) removeTranslationHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	lang := httprouter.ParamsFromContext(r.Context()).ByName("lang")
+
+	log := app.logger.With(
+		slog.String("song", strconv.FormatInt(id, 10)),
+		slog.String("lang", lang))
+
+	log.Info("attempting to remove lyrics translation")
+
+	err = app.models.Translations.As(app.actor(r)).Delete(id, lang)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrNoRecordFound):
+			app.notFoundResponse(w, r)
+			app.logger.Warn("lyrics translation not found", slog.Any("error", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+			app.logger.Error("failed to remove lyrics translation", slog.Any("error", err))
+		}
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "translation successfully removed"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		app.logger.Error("failed to remove lyrics translation", slog.Any("error", err))
+	}
+
+	log.Info("lyrics translation removed successfully")
+}
@@ -0,0 +1,280 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"test_task/internal/validator"
+	"time"
+)
+
+// @Description Album data structure
+// @Schema
+type Album struct {
+	ID          int64     `json:"id"`
+	Title       string    `json:"title"`
+	Group       string    `json:"group"`
+	GroupID     int64     `json:"group_id"`
+	ReleaseDate *Date     `json:"release_date,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+}
+
+type AlbumModel struct {
+	DB    *sql.DB
+	actor Actor
+}
+
+// As returns a copy of the model that attributes its changes to actor.
+func (m AlbumModel) As(actor Actor) AlbumModel {
+	m.actor = actor
+	return m
+}
+
+// songs returns the song model that records track changes in the audit log
+// on behalf of the model's actor.
+func (m AlbumModel) songs() SongModel {
+	return SongModel{DB: m.DB, actor: m.actor}
+}
+
+func ValidateAlbum(v *validator.Validator, album *Album) {
+	v.Check(album.Title != "", "title", "must be provided")
+	v.Check(len(album.Title) <= 255, "title", "must not be more than 255 bytes long")
+	v.Check(album.Group != "", "group", "must be provided")
+}
+
+func ValidateTrackNumber(v *validator.Validator, number int) {
+	v.Check(number > 0, "track_number", "must be greater than zero")
+	v.Check(number <= 1000, "track_number", "must be a maximum of 1000")
+}
+
+// Insert adds an album, creating its group if it doesn't exist yet.
+func (m AlbumModel) Insert(album *Album) error {
+	query := `
+WITH grp AS (
+    INSERT INTO groups (name)
+    VALUES ($2)
+    ON CONFLICT (lower(name)) DO UPDATE SET name = groups.name
+    RETURNING id, name),
+album AS (
+    INSERT INTO albums (title, group_id, release_date)
+    SELECT $1, grp.id, $3 FROM grp
+    RETURNING id, created_at, updated_at)
+SELECT album.id, album.created_at, album.updated_at, grp.id, grp.name
+FROM album, grp`
+
+	args := []any{album.Title, NormaliseGroupName(album.Group), album.ReleaseDate}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, args...).Scan(
+		&album.ID,
+		&album.CreatedAt,
+		&album.UpdatedAt,
+		&album.GroupID,
+		&album.Group)
+
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return ErrDuplicateAlbum
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (m AlbumModel) Get(id int64) (*Album, error) {
+	if id < 1 {
+		return nil, ErrNoRecordFound
+	}
+
+	query := `
+SELECT a.id, a.title, a.group_id, g.name, a.release_date, a.created_at, a.updated_at
+FROM albums a
+JOIN groups g ON g.id = a.group_id
+WHERE a.id = $1`
+
+	var album Album
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, id).Scan(
+		&album.ID,
+		&album.Title,
+		&album.GroupID,
+		&album.Group,
+		&album.ReleaseDate,
+		&album.CreatedAt,
+		&album.UpdatedAt)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrNoRecordFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &album, nil
+}
+
+func (m AlbumModel) GetAll(title, group string, filters Filters) ([]*Album, Metadata, error) {
+	query := fmt.Sprintf(`
+SELECT count(*) OVER(), a.id, a.title, a.group_id, g.name, a.release_date, a.created_at, a.updated_at
+FROM albums a
+JOIN groups g ON g.id = a.group_id
+WHERE (to_tsvector('simple', a.title) @@ plainto_tsquery('simple', $1) OR $1 = '')
+AND (to_tsvector('simple', g.name) @@ plainto_tsquery('simple', $2) OR $2 = '')
+ORDER BY %s %s NULLS LAST, a.id ASC
+LIMIT $3 OFFSET $4`,
+		albumSortColumns[filters.sortColumn()], filters.sortDirection())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, title, group, filters.limit(), filters.offset())
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	albums := []*Album{}
+
+	for rows.Next() {
+		var album Album
+
+		err := rows.Scan(
+			&totalRecords,
+			&album.ID,
+			&album.Title,
+			&album.GroupID,
+			&album.Group,
+			&album.ReleaseDate,
+			&album.CreatedAt,
+			&album.UpdatedAt)
+
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		albums = append(albums, &album)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	metadata := calculateMetaData(totalRecords, filters.Page, filters.PageSize)
+
+	return albums, metadata, nil
+}
+
+var albumSortColumns = map[string]string{
+	"id":           "a.id",
+	"title":        "a.title",
+	"group":        "g.name",
+	"release_date": "a.release_date",
+}
+
+// GetTracks returns the songs of an album in track order.
+func (m AlbumModel) GetTracks(id int64) ([]*Song, error) {
+	query := `
+SELECT ` + songColumns + `
+FROM ` + songTables + `
+WHERE s.album_id = $1 AND s.deleted_at IS NULL
+ORDER BY s.track_number`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	songs := []*Song{}
+
+	for rows.Next() {
+		var song Song
+
+		err := rows.Scan(song.fields()...)
+		if err != nil {
+			return nil, err
+		}
+
+		songs = append(songs, &song)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return songs, nil
+}
+
+// SetTrack puts a song on an album under the given track number, moving it
+// off any album it was on before. The song must belong to the album's group.
+func (m AlbumModel) SetTrack(albumID, songID int64, number int) error {
+	query := `
+UPDATE songs
+SET album_id = $1, track_number = $2, version = version + 1, updated_at = NOW()
+WHERE id = $3 AND deleted_at IS NULL
+AND group_id = (SELECT group_id FROM albums WHERE id = $1)`
+
+	err := m.songs().exec(songID, AuditUpdate, query, albumID, number, songID)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return ErrTrackTaken
+		}
+		return err
+	}
+
+	return nil
+}
+
+// RemoveTrack takes the song with the given track number off an album.
+func (m AlbumModel) RemoveTrack(albumID int64, number int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var songID int64
+
+	err = tx.QueryRowContext(ctx, `
+SELECT id FROM songs
+WHERE album_id = $1 AND track_number = $2 AND deleted_at IS NULL
+FOR UPDATE`, albumID, number).Scan(&songID)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrNoRecordFound
+		default:
+			return err
+		}
+	}
+
+	err = m.songs().audited(ctx, tx, songID, AuditUpdate, func() error {
+		_, err := tx.ExecContext(ctx, `UPDATE songs SET album_id = NULL, track_number = NULL, version = version + 1, updated_at = NOW() WHERE id = $1`, songID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
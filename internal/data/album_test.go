@@ -0,0 +1,48 @@
+package data
+
+import (
+	"errors"
+	"test_task/internal/testdb"
+	"testing"
+)
+
+func TestAlbumSetTrack(t *testing.T) {
+	models := NewModels(testdb.New(t))
+	song := insertSong(t, models, "Muse", "Uprising")
+	other := insertSong(t, models, "Placebo", "Every You Every Me")
+
+	album := &Album{Title: "The Resistance", Group: "Muse"}
+
+	err := models.Albums.Insert(album)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = models.Albums.SetTrack(album.ID, other.ID, 1)
+	if !errors.Is(err, ErrNoRecordFound) {
+		t.Errorf("SetTrack of another group's song: got %v; want %v", err, ErrNoRecordFound)
+	}
+
+	err = models.Albums.SetTrack(album.ID, song.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = models.Albums.RemoveTrack(album.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := getSong(t, models, song.ID); got.AlbumID != 0 || got.Version != song.Version+2 {
+		t.Errorf("got album %d, version %d; want no album, version %d", got.AlbumID, got.Version, song.Version+2)
+	}
+
+	entries, _, err := models.Audit.GetAll(AuditFilter{SongID: song.ID, Action: AuditUpdate, Filters: Filters{Page: 1, PageSize: 100}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 2 {
+		t.Errorf("got %d update entries; want one for setting and one for removing the track", len(entries))
+	}
+}
@@ -0,0 +1,246 @@
+package data
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"test_task/internal/validator"
+	"time"
+)
+
+const (
+	AuditInsert  = "insert"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+	AuditPurge   = "purge"
+)
+
+var auditActions = []string{AuditInsert, AuditUpdate, AuditDelete, AuditRestore, AuditPurge}
+
+// @Description Audit log entry data structure
+// @Schema
+type AuditEntry struct {
+	ID        int64                  `json:"id"`
+	SongID    int64                  `json:"song_id"`
+	Action    string                 `json:"action"`
+	Actor     string                 `json:"actor"`
+	RequestID string                 `json:"request_id,omitempty"`
+	Changes   map[string]AuditChange `json:"changes"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// AuditChange holds the stored values of a song column before and after a
+// change, as JSON. Before is null for an insert, After for a purge.
+type AuditChange struct {
+	Before json.RawMessage `json:"before" swaggertype:"object"`
+	After  json.RawMessage `json:"after" swaggertype:"object"`
+}
+
+// AuditFilter holds the optional filters of an audit log listing.
+type AuditFilter struct {
+	SongID    int64
+	Actor     string
+	Action    string
+	RequestID string
+	From      *Date
+	To        *Date
+
+	Filters
+}
+
+func ValidateAuditFilter(v *validator.Validator, f AuditFilter) {
+	v.Check(f.Action == "" || validator.PermittedValue(f.Action, auditActions...), "action", "invalid action value")
+	if f.From != nil && f.To != nil {
+		v.Check(!f.To.Before(f.From.Time), "to", "must not be before from")
+	}
+
+	ValidatePagination(v, f.Filters)
+}
+
+type AuditModel struct {
+	DB *sql.DB
+}
+
+// GetAll lists audit log entries matching the filter, newest first.
+func (m AuditModel) GetAll(filter AuditFilter) ([]*AuditEntry, Metadata, error) {
+	query := `
+SELECT count(*) OVER(), id, song_id, action, actor, request_id, changes, created_at
+FROM audit_log
+WHERE (song_id = $1 OR $1 = 0)
+AND (actor = $2 OR $2 = '')
+AND (action = $3 OR $3 = '')
+AND (request_id = $4 OR $4 = '')
+AND (created_at >= $5 OR $5 IS NULL)
+AND (created_at < $6::DATE + 1 OR $6 IS NULL)
+ORDER BY id DESC
+LIMIT $7 OFFSET $8`
+
+	args := []any{filter.SongID, filter.Actor, filter.Action, filter.RequestID, filter.From, filter.To,
+		filter.limit(), filter.offset()}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	entries := []*AuditEntry{}
+
+	for rows.Next() {
+		var entry AuditEntry
+		var changes []byte
+
+		err := rows.Scan(
+			&totalRecords,
+			&entry.ID,
+			&entry.SongID,
+			&entry.Action,
+			&entry.Actor,
+			&entry.RequestID,
+			&changes,
+			&entry.CreatedAt)
+
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		err = json.Unmarshal(changes, &entry.Changes)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		entries = append(entries, &entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	metadata := calculateMetaData(totalRecords, filter.Page, filter.PageSize)
+
+	return entries, metadata, nil
+}
+
+// songSnapshot returns the stored columns of a song as JSON values, together
+// with the name of its group and what belongs to the song: credits, tags,
+// links, relations, translations and lyric timings. The bookkeeping columns
+// that change on every write are left out. A missing song has a nil
+// snapshot.
+func songSnapshot(ctx context.Context, tx *sql.Tx, songID int64) (map[string]json.RawMessage, error) {
+	query := `
+SELECT to_jsonb(s) - 'updated_at' - 'version' - 'details_fetched_at' - 'resync_failures' - 'resync_retry_at' - 'lyrics_edited_at' - 'credits_key'
+       || jsonb_build_object(
+           'group', (SELECT name FROM groups WHERE id = s.group_id),
+           'credits', COALESCE((
+               SELECT jsonb_agg(jsonb_build_object('group_id', sa.group_id, 'group', g.name, 'role', sa.role) ORDER BY sa.role, sa.group_id)
+               FROM song_artists sa JOIN groups g ON g.id = sa.group_id WHERE sa.song_id = s.id), '[]'),
+           'tags', COALESCE((
+               SELECT jsonb_agg(jsonb_build_object('kind', t.kind, 'name', t.name) ORDER BY t.kind, lower(t.name))
+               FROM song_tags st JOIN tags t ON t.id = st.tag_id WHERE st.song_id = s.id), '[]'),
+           'links', COALESCE((
+               SELECT jsonb_agg(jsonb_build_object('platform', l.platform, 'url', l.url, 'primary', l.is_primary) ORDER BY l.platform)
+               FROM song_links l WHERE l.song_id = s.id), '[]'),
+           'related', COALESCE((
+               SELECT jsonb_agg(jsonb_build_object('type', r.type, 'song_id', r.related_song_id) ORDER BY r.type, r.related_song_id)
+               FROM song_relations r WHERE r.song_id = s.id), '[]'),
+           'related_by', COALESCE((
+               SELECT jsonb_agg(jsonb_build_object('type', r.type, 'song_id', r.song_id) ORDER BY r.type, r.song_id)
+               FROM song_relations r WHERE r.related_song_id = s.id), '[]'),
+           'translations', COALESCE((
+               SELECT jsonb_object_agg(lt.language, lt.text)
+               FROM lyric_translations lt WHERE lt.song_id = s.id), '{}'),
+           'timings', COALESCE((
+               SELECT jsonb_object_agg(lt.line, lt.offset_ms)
+               FROM lyric_timings lt WHERE lt.song_id = s.id), '{}'))
+FROM songs s
+WHERE id = $1`
+
+	var row []byte
+
+	err := tx.QueryRowContext(ctx, query, songID).Scan(&row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var snapshot map[string]json.RawMessage
+
+	err = json.Unmarshal(row, &snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// diffSnapshots returns the columns whose values differ between two song
+// snapshots.
+func diffSnapshots(before, after map[string]json.RawMessage) map[string]AuditChange {
+	changes := make(map[string]AuditChange)
+
+	for column, value := range before {
+		if !bytes.Equal(value, after[column]) {
+			changes[column] = AuditChange{Before: value, After: after[column]}
+		}
+	}
+
+	for column, value := range after {
+		if _, ok := before[column]; !ok {
+			changes[column] = AuditChange{After: value}
+		}
+	}
+
+	return changes
+}
+
+// audited runs fn, which changes the song songID inside tx, and records what
+// it changed in the audit log on behalf of the model's actor. Nothing is
+// recorded when fn leaves the song as it was.
+func (s SongModel) audited(ctx context.Context, tx *sql.Tx, songID int64, action string, fn func() error) error {
+	before, err := songSnapshot(ctx, tx, songID)
+	if err != nil {
+		return err
+	}
+
+	err = fn()
+	if err != nil {
+		return err
+	}
+
+	after, err := songSnapshot(ctx, tx, songID)
+	if err != nil {
+		return err
+	}
+
+	return s.recordAudit(ctx, tx, songID, action, before, after)
+}
+
+// recordAudit stores the difference between two snapshots of a song in the
+// audit log on behalf of the model's actor.
+func (s SongModel) recordAudit(ctx context.Context, tx *sql.Tx, songID int64, action string, before, after map[string]json.RawMessage) error {
+	changes := diffSnapshots(before, after)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	query := `
+INSERT INTO audit_log (song_id, action, actor, request_id, changes)
+VALUES ($1, $2, $3, $4, $5)`
+
+	_, err = tx.ExecContext(ctx, query, songID, action, s.author(), s.actor.RequestID, payload)
+	return err
+}
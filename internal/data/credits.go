@@ -0,0 +1,115 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"test_task/internal/validator"
+)
+
+// Roles a group can be credited with on a song besides being its primary
+// group. Only featured groups tell one recording apart from another.
+const (
+	RoleFeat     = "feat"
+	RoleProducer = "producer"
+	RoleComposer = "composer"
+)
+
+// @Description Group credited on a song
+// @Schema
+type Credit struct {
+	GroupID int64  `json:"group_id"`
+	Group   string `json:"group"`
+	Role    string `json:"role"`
+}
+
+// Credits are the credited groups of a song as read from the database.
+type Credits []Credit
+
+func (c *Credits) Scan(src any) error {
+	b, ok := src.([]byte)
+	if !ok {
+		return errors.New("credits: unsupported type")
+	}
+
+	return json.Unmarshal(b, c)
+}
+
+func ValidateCredits(v *validator.Validator, group string, credits []Credit) {
+	v.Check(len(credits) <= 20, "credits", "must not have more than 20 entries")
+
+	seen := make(map[string]bool, len(credits))
+
+	for _, c := range credits {
+		name := strings.ToLower(NormaliseGroupName(c.Group))
+
+		v.Check(validator.PermittedValue(c.Role, RoleFeat, RoleProducer, RoleComposer), "credits", "role must be feat, producer or composer")
+		v.Check(name != "", "credits", "group must be provided")
+		v.Check(len(c.Group) <= 255, "credits", "group must not be more than 255 bytes long")
+		v.Check(c.Role != RoleFeat || name != strings.ToLower(NormaliseGroupName(group)), "credits", "the song's own group can't be featured")
+		v.Check(!seen[name+"\x00"+c.Role], "credits", "must not list a group twice with the same role")
+
+		seen[name+"\x00"+c.Role] = true
+	}
+}
+
+// creditsKey identifies a song's featured groups for the uniqueness of songs.
+func creditsKey(credits []Credit) string {
+	var ids []int64
+
+	for _, c := range credits {
+		if c.Role == RoleFeat {
+			ids = append(ids, c.GroupID)
+		}
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = strconv.FormatInt(id, 10)
+	}
+
+	return strings.Join(keys, ",")
+}
+
+// upsertCreditGroups creates the credited groups that don't exist yet and
+// fills in the ids and stored names of all of them.
+func upsertCreditGroups(ctx context.Context, tx *sql.Tx, credits []Credit) error {
+	query := `
+INSERT INTO groups (name)
+VALUES ($1)
+ON CONFLICT (lower(name)) DO UPDATE SET name = groups.name
+RETURNING id, name`
+
+	for i := range credits {
+		err := tx.QueryRowContext(ctx, query, NormaliseGroupName(credits[i].Group)).Scan(&credits[i].GroupID, &credits[i].Group)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// insertCredits stores the credits of a song, whose groups must already have
+// been upserted.
+func insertCredits(ctx context.Context, tx *sql.Tx, songID int64, credits []Credit) error {
+	query := `
+INSERT INTO song_artists (song_id, group_id, role)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING`
+
+	for _, c := range credits {
+		_, err := tx.ExecContext(ctx, query, songID, c.GroupID, c.Role)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package data
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// releaseLayouts are the date formats providers are known to use.
+var releaseLayouts = []string{"02.01.2006", "2.1.2006", dateLayout}
+
+// Date is a calendar date without time of day, encoded as "YYYY-MM-DD" in
+// JSON and stored in DATE columns.
+type Date struct {
+	time.Time
+}
+
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return Date{}, errors.New("must be a date in the YYYY-MM-DD format")
+	}
+
+	return Date{t}, nil
+}
+
+func (d Date) String() string {
+	return d.Format(dateLayout)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+func (d *Date) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return errors.New("date must be a string in the YYYY-MM-DD format")
+	}
+
+	*d, err = ParseDate(s)
+	return err
+}
+
+func (d Date) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+func (d *Date) Scan(src any) error {
+	t, ok := src.(time.Time)
+	if !ok {
+		return errors.New("date: unsupported type")
+	}
+
+	d.Time = t
+	return nil
+}
+
+// ParseReleaseDate parses a release date as sent by a provider, such as
+// "16.07.2006". It returns nil when the string isn't a recognised full date.
+func ParseReleaseDate(s string) *Date {
+	s = strings.TrimSpace(s)
+
+	for _, layout := range releaseLayouts {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return &Date{t}
+		}
+	}
+
+	return nil
+}
@@ -1,7 +1,112 @@
 package data
 
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
 type SongDetail struct {
 	Release string `json:"releaseDate"`
 	Text    string `json:"text"`
 	Link    string `json:"link"`
+
+	Sources    DetailSources `json:"-"`
+	Provenance []Provenance  `json:"-"`
+
+	// Partial is set when some of the providers asked failed for a reason
+	// that may go away on a retry, so the details may be missing fields
+	// those providers would have filled.
+	Partial bool `json:"-"`
+}
+
+// Provenance is the raw answer a provider gave for a song.
+type Provenance struct {
+	Provider   string          `json:"provider"`
+	FetchedAt  time.Time       `json:"fetched_at"`
+	HTTPStatus int             `json:"http_status"`
+	Payload    json.RawMessage `json:"payload" swaggertype:"object"`
+}
+
+// DetailSources maps a detail field (releaseDate, text, link) to the name of
+// the provider its value came from.
+type DetailSources map[string]string
+
+// Merge fills the empty fields of d from other, recording source as the
+// origin of every field it fills, and keeps the provenance of other.
+func (d *SongDetail) Merge(other *SongDetail, source string) {
+	if d.Sources == nil {
+		d.Sources = make(DetailSources)
+	}
+
+	d.Provenance = append(d.Provenance, other.Provenance...)
+
+	fields := []struct {
+		name string
+		dst  *string
+		src  string
+	}{
+		{"releaseDate", &d.Release, other.Release},
+		{"text", &d.Text, other.Text},
+		{"link", &d.Link, other.Link},
+	}
+
+	for _, f := range fields {
+		if *f.dst == "" && f.src != "" {
+			*f.dst = f.src
+			d.Sources[f.name] = source
+		}
+	}
+}
+
+// Complete reports whether every detail field is filled.
+func (d *SongDetail) Complete() bool {
+	return d.Release != "" && d.Text != "" && d.Link != ""
+}
+
+func (s DetailSources) Value() (driver.Value, error) {
+	if s == nil {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(s)
+}
+
+func (s *DetailSources) Scan(src any) error {
+	b, ok := src.([]byte)
+	if !ok {
+		return errors.New("detail sources: unsupported type")
+	}
+
+	return json.Unmarshal(b, s)
+}
+
+// DetailChange describes a single detail field that differs between the
+// stored song and a fresh provider answer.
+type DetailChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+func DiffDetails(song *Song, detail *SongDetail) []DetailChange {
+	changes := []DetailChange{}
+
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"releaseDate", song.Release, detail.Release},
+		{"text", song.Text, detail.Text},
+		{"link", song.Link, song.LinkAfter(detail.Link)},
+	}
+
+	for _, f := range fields {
+		if f.old != f.new {
+			changes = append(changes, DetailChange{Field: f.name, Old: f.old, New: f.new})
+		}
+	}
+
+	return changes
 }
@@ -0,0 +1,127 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type EnrichmentJob struct {
+	ID        int64
+	SongID    int64
+	Attempts  int
+	LastError string
+}
+
+type EnrichmentJobModel struct {
+	DB *sql.DB
+}
+
+// Lease claims up to limit due jobs for the given duration. Rows already
+// locked by another worker or replica are skipped, and a job whose lease
+// expired (e.g. its worker crashed) becomes due again.
+func (m EnrichmentJobModel) Lease(lease time.Duration, limit int) ([]*EnrichmentJob, error) {
+	query := `
+UPDATE enrichment_jobs
+SET attempts = attempts + 1, locked_until = NOW() + make_interval(secs => $1), updated_at = NOW()
+WHERE id IN (
+    SELECT id
+    FROM enrichment_jobs
+    WHERE status = 'pending'
+    AND next_run_at <= NOW()
+    AND (locked_until IS NULL OR locked_until < NOW())
+    ORDER BY next_run_at, id
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED)
+RETURNING id, song_id, attempts, last_error`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, lease.Seconds(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []*EnrichmentJob
+
+	for rows.Next() {
+		var job EnrichmentJob
+
+		err := rows.Scan(&job.ID, &job.SongID, &job.Attempts, &job.LastError)
+		if err != nil {
+			return nil, err
+		}
+
+		jobs = append(jobs, &job)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// Complete marks a job as successfully done.
+func (m EnrichmentJobModel) Complete(id int64) error {
+	query := `
+UPDATE enrichment_jobs
+SET status = 'done', locked_until = NULL, last_error = '', updated_at = NOW()
+WHERE id = $1`
+
+	return m.exec(query, id)
+}
+
+// Retry releases a job so that it runs again after delay.
+func (m EnrichmentJobModel) Retry(id int64, reason string, delay time.Duration) error {
+	query := `
+UPDATE enrichment_jobs
+SET locked_until = NULL, last_error = $1, next_run_at = NOW() + make_interval(secs => $2), updated_at = NOW()
+WHERE id = $3`
+
+	return m.exec(query, reason, delay.Seconds(), id)
+}
+
+// Release gives back a job that was interrupted before it could finish, so
+// it runs again right away without the attempt counting.
+func (m EnrichmentJobModel) Release(id int64) error {
+	query := `
+UPDATE enrichment_jobs
+SET locked_until = NULL, attempts = attempts - 1, updated_at = NOW()
+WHERE id = $1`
+
+	return m.exec(query, id)
+}
+
+// Fail marks a job as permanently failed.
+func (m EnrichmentJobModel) Fail(id int64, reason string) error {
+	query := `
+UPDATE enrichment_jobs
+SET status = 'failed', locked_until = NULL, last_error = $1, updated_at = NOW()
+WHERE id = $2`
+
+	return m.exec(query, reason, id)
+}
+
+func (m EnrichmentJobModel) exec(query string, args ...any) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrNoRecordFound
+	}
+
+	return nil
+}
@@ -36,12 +36,16 @@ func calculateMetaData(totalRecords, page, pageSize int) Metadata {
 }
 
 func ValidateFilters(v *validator.Validator, f Filters) {
+	ValidatePagination(v, f)
+
+	v.Check(validator.PermittedValue(f.Sort, f.SortSafelist...), "sort", "invalid sort value")
+}
+
+func ValidatePagination(v *validator.Validator, f Filters) {
 	v.Check(f.Page > 0, "page", "must be greater than zero")
 	v.Check(f.Page <= 10_000_000, "page", "must be a maximum of 10 million")
 	v.Check(f.PageSize > 0, "page_size", "must be greater than zero")
 	v.Check(f.PageSize <= 100, "page_size", "must be a maximum of 100")
-
-	v.Check(validator.PermittedValue(f.Sort, f.SortSafelist...), "sort", "invalid sort value")
 }
 
 func (f Filters) sortColumn() string {
@@ -69,6 +73,11 @@ func (f Filters) offset() int {
 	return (f.Page - 1) * f.PageSize
 }
 
-func splitTextIntoVerses(text string) []string {
-	return strings.Split(text, "\n")
+// Paginate returns the page of items selected by f, for lists that are built
+// in memory rather than by a query.
+func Paginate[T any](items []T, f Filters) ([]T, Metadata) {
+	start := min(f.offset(), len(items))
+	end := min(start+f.limit(), len(items))
+
+	return items[start:end], calculateMetaData(len(items), f.Page, f.PageSize)
 }
@@ -0,0 +1,340 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"strings"
+	"test_task/internal/validator"
+	"time"
+)
+
+// @Description Group (artist) data structure
+// @Schema
+type Group struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+type GroupModel struct {
+	DB    *sql.DB
+	actor Actor
+}
+
+// As returns a copy of the model that attributes its changes to actor.
+func (m GroupModel) As(actor Actor) GroupModel {
+	m.actor = actor
+	return m
+}
+
+// NormaliseGroupName trims a group name and collapses inner whitespace, so
+// "Muse" and "muse " end up in the same group (names are unique regardless
+// of case).
+func NormaliseGroupName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+func ValidateGroup(v *validator.Validator, group *Group) {
+	v.Check(group.Name != "", "name", "must be provided")
+	v.Check(len(group.Name) <= 255, "name", "must not be more than 255 bytes long")
+}
+
+func (m GroupModel) Insert(group *Group) error {
+	query := `
+INSERT INTO groups (name)
+VALUES ($1)
+RETURNING id, name, created_at, updated_at`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, NormaliseGroupName(group.Name)).Scan(
+		&group.ID,
+		&group.Name,
+		&group.CreatedAt,
+		&group.UpdatedAt)
+
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return ErrDuplicateGroup
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (m GroupModel) Get(id int64) (*Group, error) {
+	if id < 1 {
+		return nil, ErrNoRecordFound
+	}
+
+	query := `
+SELECT id, name, created_at, updated_at
+FROM groups
+WHERE id = $1`
+
+	var group Group
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, id).Scan(
+		&group.ID,
+		&group.Name,
+		&group.CreatedAt,
+		&group.UpdatedAt)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrNoRecordFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &group, nil
+}
+
+func (m GroupModel) GetAll(name string, filters Filters) ([]*Group, Metadata, error) {
+	query := fmt.Sprintf(`
+SELECT count(*) OVER(), id, name, created_at, updated_at
+FROM groups
+WHERE (to_tsvector('simple', name) @@ plainto_tsquery('simple', $1) OR $1 = '')
+ORDER BY %s %s, id ASC
+LIMIT $2 OFFSET $3`,
+		filters.sortColumn(), filters.sortDirection())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, name, filters.limit(), filters.offset())
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	groups := []*Group{}
+
+	for rows.Next() {
+		var group Group
+
+		err := rows.Scan(
+			&totalRecords,
+			&group.ID,
+			&group.Name,
+			&group.CreatedAt,
+			&group.UpdatedAt)
+
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		groups = append(groups, &group)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	metadata := calculateMetaData(totalRecords, filters.Page, filters.PageSize)
+
+	return groups, metadata, nil
+}
+
+// Update renames a group. The songs of the group and the songs crediting it
+// show the new name, so their versions are bumped and the rename is recorded
+// in their audit log on behalf of the model's actor.
+func (m GroupModel) Update(group *Group) error {
+	query := `
+UPDATE groups
+SET name = $1, updated_at = NOW()
+WHERE id = $2
+RETURNING name, updated_at`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var oldName string
+
+	err = tx.QueryRowContext(ctx, `SELECT name FROM groups WHERE id = $1 FOR UPDATE`, group.ID).Scan(&oldName)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrNoRecordFound
+		default:
+			return err
+		}
+	}
+
+	ids, err := lockGroupSongs(ctx, tx, group.ID)
+	if err != nil {
+		return err
+	}
+
+	before := make([]map[string]json.RawMessage, len(ids))
+	for i, id := range ids {
+		before[i], err = songSnapshot(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.QueryRowContext(ctx, query, NormaliseGroupName(group.Name), group.ID).Scan(
+		&group.Name,
+		&group.UpdatedAt)
+
+	if err != nil {
+		var pqErr *pq.Error
+		switch {
+		case errors.As(err, &pqErr) && pqErr.Code == "23505":
+			return ErrDuplicateGroup
+		default:
+			return err
+		}
+	}
+
+	if group.Name != oldName {
+		_, err = tx.ExecContext(ctx, `UPDATE songs SET version = version + 1, updated_at = NOW() WHERE id = ANY($1)`, pq.Array(ids))
+		if err != nil {
+			return err
+		}
+
+		songs := SongModel{DB: m.DB, actor: m.actor}
+
+		for i, id := range ids {
+			after, err := songSnapshot(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+
+			err = songs.recordAudit(ctx, tx, id, AuditUpdate, before[i], after)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// lockGroupSongs locks the songs of a group and the songs crediting it, in
+// the trash or not, and returns their ids.
+func lockGroupSongs(ctx context.Context, tx *sql.Tx, groupID int64) ([]int64, error) {
+	query := `
+SELECT id FROM songs
+WHERE group_id = $1 OR id IN (SELECT song_id FROM song_artists WHERE group_id = $1)
+ORDER BY id
+FOR UPDATE`
+
+	rows, err := tx.QueryContext(ctx, query, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+
+	for rows.Next() {
+		var id int64
+
+		err := rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// Delete removes a group. Groups that still have songs or albums, or are
+// credited on songs, can't be deleted.
+func (m GroupModel) Delete(id int64) error {
+	if id < 1 {
+		return ErrNoRecordFound
+	}
+
+	query := `
+DELETE FROM groups
+WHERE id = $1`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, query, id)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			// The error names the table that still refers to the group.
+			switch pqErr.Table {
+			case "albums":
+				return ErrGroupHasAlbums
+			case "song_artists":
+				return ErrGroupHasCredits
+			default:
+				return ErrGroupHasSongs
+			}
+		}
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrNoRecordFound
+	}
+
+	return nil
+}
+
+// deleteOrphanGroup deletes a group once no song, credit or album refers to
+// it. A group that a concurrent transaction has just started using again is
+// kept, without failing tx.
+func deleteOrphanGroup(ctx context.Context, tx *sql.Tx, id int64) error {
+	query := `
+DELETE FROM groups g
+WHERE g.id = $1
+AND NOT EXISTS (SELECT 1 FROM songs WHERE group_id = g.id)
+AND NOT EXISTS (SELECT 1 FROM song_artists WHERE group_id = g.id)
+AND NOT EXISTS (SELECT 1 FROM albums WHERE group_id = g.id)`
+
+	_, err := tx.ExecContext(ctx, `SAVEPOINT delete_orphan_group`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, id)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			_, err = tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT delete_orphan_group`)
+		}
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `RELEASE SAVEPOINT delete_orphan_group`)
+	return err
+}
@@ -0,0 +1,35 @@
+package data
+
+import (
+	"test_task/internal/testdb"
+	"testing"
+)
+
+func TestGroupRenameIsAudited(t *testing.T) {
+	models := NewModels(testdb.New(t))
+	song := insertSong(t, models, "Muse", "Uprising")
+
+	err := models.Groups.As(Actor{Name: "alice"}).Update(&Group{ID: song.GroupID, Name: "MUSE"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	renamed := getSong(t, models, song.ID)
+	if renamed.Group != "MUSE" || renamed.Version != song.Version+1 {
+		t.Errorf("got group %q, version %d; want MUSE, %d", renamed.Group, renamed.Version, song.Version+1)
+	}
+
+	entries, _, err := models.Audit.GetAll(AuditFilter{SongID: song.ID, Action: AuditUpdate, Filters: Filters{Page: 1, PageSize: 100}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 || entries[0].Actor != "alice" {
+		t.Fatalf("got %d update entries; want one by alice", len(entries))
+	}
+
+	change := entries[0].Changes["group"]
+	if string(change.Before) != `"Muse"` || string(change.After) != `"MUSE"` {
+		t.Errorf("got group %s -> %s; want \"Muse\" -> \"MUSE\"", change.Before, change.After)
+	}
+}
@@ -0,0 +1,321 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"maps"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	lrcTimestampRX = regexp.MustCompile(`^\[(\d+):(\d{1,2})(?:[.:](\d{1,3}))?\]`)
+	lrcMetadataRX  = regexp.MustCompile(`^\[[a-zA-Z]+:[^\]]*\]$`)
+)
+
+// Timings maps line numbers, as numbered by SplitLines, to the offset in
+// milliseconds from the start of the song at which the line is sung.
+type Timings map[int]int64
+
+// ApplyToLines sets the offsets of lines that have a timing.
+func (t Timings) ApplyToLines(lines []Line) {
+	for i := range lines {
+		if offset, ok := t[lines[i].Number]; ok {
+			lines[i].Offset = &offset
+		}
+	}
+}
+
+// ApplyToVerses sets the offsets of the lines of verses, which must hold all
+// stanzas of the lyrics so their lines can be numbered. Only verses whose
+// every line is timed get offsets.
+func (t Timings) ApplyToVerses(verses []Verse) {
+	line := 0
+
+	for i := range verses {
+		offsets := make([]int64, 0, len(verses[i].Lines))
+
+		for range verses[i].Lines {
+			line++
+			if offset, ok := t[line]; ok {
+				offsets = append(offsets, offset)
+			}
+		}
+
+		if len(offsets) == len(verses[i].Lines) {
+			verses[i].Offsets = offsets
+		}
+	}
+}
+
+// ParseLRC reads lyrics in the LRC format, e.g. "[00:12.34]First line".
+// Timestamped empty lines and untimed blank lines separate stanzas; metadata
+// tags such as [ar:...] are ignored. Timestamps must strictly increase
+// through the file, so a repeated line has to be written out each time
+// rather than given several timestamps.
+func ParseLRC(src string) (string, Timings, error) {
+	var lines []string
+	timings := Timings{}
+
+	number := 0
+	last := int64(-1)
+
+	for i, raw := range splitLyricLines(src) {
+		raw = strings.TrimSpace(raw)
+
+		if raw == "" {
+			lines = append(lines, "")
+			continue
+		}
+
+		if lrcMetadataRX.MatchString(raw) {
+			continue
+		}
+
+		var offsets []int64
+
+		for {
+			m := lrcTimestampRX.FindStringSubmatch(raw)
+			if m == nil {
+				break
+			}
+
+			offset, ok := lrcOffset(m[1], m[2], m[3])
+			if !ok {
+				return "", nil, fmt.Errorf("line %d: seconds must be below 60", i+1)
+			}
+			if offset <= last {
+				return "", nil, fmt.Errorf("line %d: timestamps must increase", i+1)
+			}
+
+			last = offset
+			offsets = append(offsets, offset)
+			raw = raw[len(m[0]):]
+		}
+
+		if len(offsets) == 0 {
+			return "", nil, fmt.Errorf("line %d: must start with a timestamp", i+1)
+		}
+
+		text := strings.TrimSpace(raw)
+
+		for _, offset := range offsets {
+			if text == "" {
+				lines = append(lines, "")
+				continue
+			}
+
+			number++
+			timings[number] = offset
+			lines = append(lines, text)
+		}
+	}
+
+	if number == 0 {
+		return "", nil, errors.New("must contain at least one timed line")
+	}
+
+	return strings.Trim(strings.Join(lines, "\n"), "\n"), timings, nil
+}
+
+// lrcOffset converts the parts of an LRC timestamp into milliseconds. It
+// reports false if the seconds are out of range.
+func lrcOffset(minutes, seconds, fraction string) (int64, bool) {
+	m, _ := strconv.ParseInt(minutes, 10, 64)
+	s, _ := strconv.ParseInt(seconds, 10, 64)
+
+	if s >= 60 {
+		return 0, false
+	}
+
+	var ms int64
+	if fraction != "" {
+		ms, _ = strconv.ParseInt((fraction + "00")[:3], 10, 64)
+	}
+
+	return m*60_000 + s*1000 + ms, true
+}
+
+// lrcTagReplacer keeps values written into LRC tags from closing the tag or
+// breaking the line.
+var lrcTagReplacer = strings.NewReplacer("[", "(", "]", ")", "\r\n", " ", "\n", " ", "\r", " ")
+
+// FormatLRC writes timed lyrics in the LRC format, with millisecond
+// timestamps so that every line keeps its exact offset. Blank lines between
+// stanzas are kept untimed.
+func FormatLRC(title, artist, text string, timings Timings) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "[ti:%s]\n[ar:%s]\n\n", lrcTagReplacer.Replace(title), lrcTagReplacer.Replace(artist))
+
+	number := 0
+
+	for _, line := range splitLyricLines(strings.Trim(text, "\n")) {
+		if line == "" {
+			b.WriteString("\n")
+			continue
+		}
+
+		number++
+		if offset, ok := timings[number]; ok {
+			d := time.Duration(offset) * time.Millisecond
+			fmt.Fprintf(&b, "[%02d:%02d.%03d]", int(d.Minutes()), int(d.Seconds())%60, d.Milliseconds()%1000)
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// GetTimedLyrics returns the lyrics of a song together with their timings,
+// which are empty if the lyrics are not synced.
+func (s SongModel) GetTimedLyrics(id int64) (string, Timings, error) {
+	if id < 1 {
+		return "", nil, ErrNoRecordFound
+	}
+
+	query := `
+SELECT s.text, array_remove(array_agg(t.line ORDER BY t.line), NULL), array_remove(array_agg(t.offset_ms ORDER BY t.line), NULL)
+FROM songs s
+LEFT JOIN lyric_timings t ON t.song_id = s.id
+WHERE s.id = $1 AND s.deleted_at IS NULL
+GROUP BY s.id`
+
+	var text string
+	var lines []int64
+	var offsets []int64
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := s.DB.QueryRowContext(ctx, query, id).Scan(&text, pq.Array(&lines), pq.Array(&offsets))
+
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return "", nil, ErrNoRecordFound
+		default:
+			return "", nil, err
+		}
+	}
+
+	timings := Timings{}
+	for i := range lines {
+		timings[int(lines[i])] = offsets[i]
+	}
+
+	return text, timings, nil
+}
+
+// SetTimedLyrics replaces the lyrics of a song and their timings, bumping
+// the song's version if either changed. Changed lyrics are recorded as a new
+// revision. Either way the lyrics count as edited by users, so fetched
+// details don't replace them.
+func (s SongModel) SetTimedLyrics(id int64, text string, timings Timings) error {
+	query := `
+INSERT INTO lyric_timings (song_id, line, offset_ms)
+SELECT $1, t.line, t.offset_ms
+FROM unnest($2::integer[], $3::bigint[]) AS t(line, offset_ms)`
+
+	lines := make([]int64, 0, len(timings))
+	offsets := make([]int64, 0, len(timings))
+
+	for line, offset := range timings {
+		lines = append(lines, int64(line))
+		offsets = append(offsets, offset)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	oldText, err := lockLyrics(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	err = s.audited(ctx, tx, id, AuditUpdate, func() error {
+		changed := text != oldText
+
+		if changed {
+			_, err := tx.ExecContext(ctx, `UPDATE songs SET text = $1, lyrics_edited_at = NOW() WHERE id = $2`, text, id)
+			if err != nil {
+				return err
+			}
+
+			// The new revision drops the timings of the old text.
+			err = appendLyricsRevision(ctx, tx, id, s.author(), text)
+			if err != nil {
+				return err
+			}
+		} else {
+			_, err := tx.ExecContext(ctx, `UPDATE songs SET lyrics_edited_at = COALESCE(lyrics_edited_at, NOW()) WHERE id = $1`, id)
+			if err != nil {
+				return err
+			}
+
+			old, err := deleteTimings(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+
+			changed = !maps.Equal(old, timings)
+		}
+
+		_, err := tx.ExecContext(ctx, query, id, pq.Array(lines), pq.Array(offsets))
+		if err != nil {
+			return err
+		}
+
+		if !changed {
+			return nil
+		}
+
+		return touchSong(ctx, tx, id)
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// deleteTimings deletes the timings of a song and returns them.
+func deleteTimings(ctx context.Context, tx *sql.Tx, id int64) (Timings, error) {
+	rows, err := tx.QueryContext(ctx, `DELETE FROM lyric_timings WHERE song_id = $1 RETURNING line, offset_ms`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	timings := Timings{}
+
+	for rows.Next() {
+		var line int
+		var offset int64
+
+		err := rows.Scan(&line, &offset)
+		if err != nil {
+			return nil, err
+		}
+
+		timings[line] = offset
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return timings, nil
+}
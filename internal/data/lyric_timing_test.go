@@ -0,0 +1,77 @@
+package data
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLRC(t *testing.T) {
+	src := "[ti:Uprising]\n[ar:Muse]\n[00:01.50]Paranoia is in bloom\n[00:03.005]The PR transmissions will resume\n[00:05.00]\n[01:02]They will not force us\n"
+
+	text, timings, err := ParseLRC(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "Paranoia is in bloom\nThe PR transmissions will resume\n\nThey will not force us"; text != want {
+		t.Errorf("text = %q, want %q", text, want)
+	}
+
+	want := Timings{1: 1500, 2: 3005, 3: 62_000}
+	if len(timings) != len(want) {
+		t.Fatalf("timings = %v, want %v", timings, want)
+	}
+	for line, offset := range want {
+		if timings[line] != offset {
+			t.Errorf("timings[%d] = %d, want %d", line, timings[line], offset)
+		}
+	}
+}
+
+func TestParseLRCErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"untimed line", "[00:01.00]One\nTwo", "line 2: must start with a timestamp"},
+		{"decreasing", "[00:02.00]One\n[00:01.00]Two", "line 2: timestamps must increase"},
+		{"repeated", "[00:01.00]One\n[00:01.00]Two", "line 2: timestamps must increase"},
+		{"seconds out of range", "[00:75.00]One", "line 1: seconds must be below 60"},
+		{"no lines", "[ar:Muse]\n\n", "must contain at least one timed line"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ParseLRC(tt.src)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("ParseLRC error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatLRCRoundTrip(t *testing.T) {
+	text := "One\nTwo\n\nThree"
+	timings := Timings{1: 1000, 2: 1005, 3: 61_234}
+
+	lrc := FormatLRC("Song [Live]\nVersion", "Group]", text, timings)
+
+	if !strings.HasPrefix(lrc, "[ti:Song (Live) Version]\n[ar:Group)]\n") {
+		t.Errorf("tags not sanitised:\n%s", lrc)
+	}
+
+	gotText, gotTimings, err := ParseLRC(lrc)
+	if err != nil {
+		t.Fatalf("ParseLRC of exported lyrics: %v\n%s", err, lrc)
+	}
+
+	if gotText != text {
+		t.Errorf("text = %q, want %q", gotText, text)
+	}
+	for line, offset := range timings {
+		if gotTimings[line] != offset {
+			t.Errorf("timings[%d] = %d, want %d", line, gotTimings[line], offset)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
+	"test_task/internal/validator"
+	"time"
+)
+
+// languageTagRX matches the common shape of BCP-47 language tags: a language,
+// then an optional script, region and variants, e.g. "en", "pt-BR",
+// "zh-Hant-TW" or "sl-rozaj".
+var languageTagRX = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z]{4})?(-([a-zA-Z]{2}|[0-9]{3}))?(-([a-zA-Z0-9]{5,8}|[0-9][a-zA-Z0-9]{3}))*$`)
+
+// @Description Translation of the lyrics of a song
+// @Schema
+type LyricTranslation struct {
+	Language  string    `json:"language"`
+	Text      string    `json:"text,omitempty"`
+	Author    string    `json:"author"`
+	Aligned   bool      `json:"aligned"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// VersePair is a stanza of the original lyrics next to the stanza with the
+// same number in a translation.
+type VersePair struct {
+	Number      int      `json:"number"`
+	Original    []string `json:"original"`
+	Translation []string `json:"translation"`
+}
+
+// maxLanguageTagLength is the longest language tag that fits the
+// lyric_translations.language column.
+const maxLanguageTagLength = 35
+
+// CanonicalLanguageTag checks that tag looks like a BCP-47 language tag of at
+// most 35 characters and returns it in canonical case ("pt-br" becomes
+// "pt-BR").
+func CanonicalLanguageTag(tag string) (string, bool) {
+	if len(tag) > maxLanguageTagLength || !languageTagRX.MatchString(tag) {
+		return "", false
+	}
+
+	subtags := strings.Split(tag, "-")
+	subtags[0] = strings.ToLower(subtags[0])
+
+	for i := 1; i < len(subtags); i++ {
+		switch {
+		case len(subtags[i]) == 4 && i == 1 && !strings.ContainsAny(subtags[i][:1], "0123456789"):
+			subtags[i] = strings.ToUpper(subtags[i][:1]) + strings.ToLower(subtags[i][1:])
+		case len(subtags[i]) == 2:
+			subtags[i] = strings.ToUpper(subtags[i])
+		default:
+			subtags[i] = strings.ToLower(subtags[i])
+		}
+	}
+
+	return strings.Join(subtags, "-"), true
+}
+
+// ValidateTranslation checks a translation against the original lyrics: it
+// must have as many stanzas, so they can be shown side by side.
+func ValidateTranslation(v *validator.Validator, translation *LyricTranslation, original string) {
+	_, ok := CanonicalLanguageTag(translation.Language)
+	v.Check(ok, "language", "must be a BCP-47 language tag of at most 35 characters")
+	v.Check(strings.TrimSpace(translation.Text) != "", "text", "must be provided")
+	v.Check(strings.TrimSpace(original) != "", "text", "the song has no lyrics to translate")
+
+	v.Check(len(SplitVerses(translation.Text)) == len(SplitVerses(original)), "text", "must have as many stanzas as the original lyrics")
+}
+
+// PairVerses puts each stanza of the original lyrics next to the translated
+// stanza with the same number. Stanzas missing on either side are empty.
+func PairVerses(original, translation []Verse) []VersePair {
+	pairs := make([]VersePair, max(len(original), len(translation)))
+
+	for i := range pairs {
+		pairs[i] = VersePair{Number: i + 1, Original: []string{}, Translation: []string{}}
+
+		if i < len(original) {
+			pairs[i].Original = original[i].Lines
+		}
+		if i < len(translation) {
+			pairs[i].Translation = translation[i].Lines
+		}
+	}
+
+	return pairs
+}
+
+type LyricTranslationModel struct {
+	DB    *sql.DB
+	actor Actor
+}
+
+// As returns a copy of the model that attributes its changes to actor.
+func (m LyricTranslationModel) As(actor Actor) LyricTranslationModel {
+	m.actor = actor
+	return m
+}
+
+// songs returns the song model that records changes in the audit log on
+// behalf of the model's actor.
+func (m LyricTranslationModel) songs() SongModel {
+	return SongModel{DB: m.DB, actor: m.actor}
+}
+
+// Put adds the translation of a song into its language or replaces the
+// existing one, and bumps the song's version. Songs in the trash can't be
+// translated.
+func (m LyricTranslationModel) Put(songID int64, translation *LyricTranslation) error {
+	query := `
+INSERT INTO lyric_translations (song_id, language, text, author)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (song_id, lower(language)) DO UPDATE
+SET language = EXCLUDED.language, text = EXCLUDED.text, author = EXCLUDED.author, updated_at = NOW()
+RETURNING created_at, updated_at`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = lockSong(ctx, tx, songID)
+	if err != nil {
+		return err
+	}
+
+	err = m.songs().audited(ctx, tx, songID, AuditUpdate, func() error {
+		err := tx.QueryRowContext(ctx, query, songID, translation.Language, translation.Text, translation.Author).Scan(
+			&translation.CreatedAt,
+			&translation.UpdatedAt)
+
+		if err != nil {
+			return err
+		}
+
+		return touchSong(ctx, tx, songID)
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Get returns the translation of a song into a language, with its text.
+func (m LyricTranslationModel) Get(songID int64, language string) (*LyricTranslation, error) {
+	query := `
+SELECT t.language, t.text, t.author, t.created_at, t.updated_at, s.text
+FROM lyric_translations t
+JOIN songs s ON s.id = t.song_id
+WHERE t.song_id = $1 AND lower(t.language) = lower($2)`
+
+	var translation LyricTranslation
+	var original string
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, songID, language).Scan(
+		&translation.Language,
+		&translation.Text,
+		&translation.Author,
+		&translation.CreatedAt,
+		&translation.UpdatedAt,
+		&original)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrNoRecordFound
+		default:
+			return nil, err
+		}
+	}
+
+	translation.Aligned = len(SplitVerses(translation.Text)) == len(SplitVerses(original))
+
+	return &translation, nil
+}
+
+// GetAll lists the translations of a song without their text. A translation
+// stops being aligned when the original lyrics change their stanzas.
+func (m LyricTranslationModel) GetAll(songID int64) ([]*LyricTranslation, error) {
+	query := `
+SELECT t.language, t.text, t.author, t.created_at, t.updated_at, s.text
+FROM lyric_translations t
+JOIN songs s ON s.id = t.song_id
+WHERE t.song_id = $1
+ORDER BY lower(t.language)`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, songID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	translations := []*LyricTranslation{}
+
+	for rows.Next() {
+		var translation LyricTranslation
+		var original string
+
+		err := rows.Scan(
+			&translation.Language,
+			&translation.Text,
+			&translation.Author,
+			&translation.CreatedAt,
+			&translation.UpdatedAt,
+			&original)
+
+		if err != nil {
+			return nil, err
+		}
+
+		translation.Aligned = len(SplitVerses(translation.Text)) == len(SplitVerses(original))
+		translation.Text = ""
+
+		translations = append(translations, &translation)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return translations, nil
+}
+
+// Delete removes the translation of a song into a language and bumps the
+// song's version. Songs in the trash are left as they are.
+func (m LyricTranslationModel) Delete(songID int64, language string) error {
+	query := `
+DELETE FROM lyric_translations
+WHERE song_id = $1 AND lower(language) = lower($2)`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = lockSong(ctx, tx, songID)
+	if err != nil {
+		return err
+	}
+
+	err = m.songs().audited(ctx, tx, songID, AuditUpdate, func() error {
+		result, err := tx.ExecContext(ctx, query, songID, language)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return ErrNoRecordFound
+		}
+
+		return touchSong(ctx, tx, songID)
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
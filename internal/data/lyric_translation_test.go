@@ -0,0 +1,32 @@
+package data
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCanonicalLanguageTag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+		ok   bool
+	}{
+		{"en", "en", true},
+		{"pt-br", "pt-BR", true},
+		{"ZH-hant-tw", "zh-Hant-TW", true},
+		{"es-419", "es-419", true},
+		{"sl-ROZAJ", "sl-rozaj", true},
+		{"de-1996", "de-1996", true},
+		{"", "", false},
+		{"english", "", false},
+		{"en_US", "", false},
+		{"en-" + strings.Repeat("abcde-", 6) + "abcde", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := CanonicalLanguageTag(tt.tag)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("CanonicalLanguageTag(%q) = %q, %v; want %q, %v", tt.tag, got, ok, tt.want, tt.ok)
+		}
+	}
+}
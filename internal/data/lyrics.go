@@ -0,0 +1,78 @@
+package data
+
+import (
+	"strings"
+)
+
+const (
+	LyricsByStanza = "stanza"
+	LyricsByLine   = "line"
+)
+
+// Verse is a stanza of lyrics: a run of lines separated from the next one by
+// a blank line. Offsets holds the millisecond offsets of its lines when the
+// lyrics are synced.
+type Verse struct {
+	Number  int      `json:"number"`
+	Lines   []string `json:"lines"`
+	Offsets []int64  `json:"offsets_ms,omitempty"`
+}
+
+// Line is a single non-blank line of lyrics, numbered from 1 across the whole
+// song. Offset is when the line is sung, in milliseconds, if it is synced.
+type Line struct {
+	Number int    `json:"number"`
+	Text   string `json:"text"`
+	Offset *int64 `json:"offset_ms,omitempty"`
+}
+
+// SplitVerses splits lyrics into stanzas on blank lines.
+func SplitVerses(text string) []Verse {
+	verses := []Verse{}
+
+	var lines []string
+	flush := func() {
+		if len(lines) > 0 {
+			verses = append(verses, Verse{Number: len(verses) + 1, Lines: lines})
+			lines = nil
+		}
+	}
+
+	for _, line := range splitLyricLines(text) {
+		if line == "" {
+			flush()
+			continue
+		}
+		lines = append(lines, line)
+	}
+	flush()
+
+	return verses
+}
+
+// SplitLines splits lyrics into their non-blank lines.
+func SplitLines(text string) []Line {
+	lines := []Line{}
+
+	for _, line := range splitLyricLines(text) {
+		if line != "" {
+			lines = append(lines, Line{Number: len(lines) + 1, Text: line})
+		}
+	}
+
+	return lines
+}
+
+func splitLyricLines(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	lines := strings.Split(text, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " \t\r")
+		if strings.TrimSpace(lines[i]) == "" {
+			lines[i] = ""
+		}
+	}
+
+	return lines
+}
@@ -0,0 +1,141 @@
+package data
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// @Description Lyrics revision data structure
+// @Schema
+type LyricsRevision struct {
+	Revision  int       `json:"revision"`
+	Author    string    `json:"author"`
+	TextHash  string    `json:"text_hash"`
+	Text      string    `json:"text,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type LyricsRevisionModel struct {
+	DB *sql.DB
+}
+
+// GetAll lists the lyrics revisions of a song, newest first, without their
+// text.
+func (m LyricsRevisionModel) GetAll(songID int64, filters Filters) ([]*LyricsRevision, Metadata, error) {
+	query := `
+SELECT count(*) OVER(), revision, author, text_hash, created_at
+FROM lyrics_revisions
+WHERE song_id = $1
+ORDER BY revision DESC
+LIMIT $2 OFFSET $3`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, songID, filters.limit(), filters.offset())
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	revisions := []*LyricsRevision{}
+
+	for rows.Next() {
+		var revision LyricsRevision
+
+		err := rows.Scan(
+			&totalRecords,
+			&revision.Revision,
+			&revision.Author,
+			&revision.TextHash,
+			&revision.CreatedAt)
+
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		revisions = append(revisions, &revision)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	metadata := calculateMetaData(totalRecords, filters.Page, filters.PageSize)
+
+	return revisions, metadata, nil
+}
+
+func (m LyricsRevisionModel) Get(songID int64, revision int) (*LyricsRevision, error) {
+	query := `
+SELECT revision, author, text_hash, text, created_at
+FROM lyrics_revisions
+WHERE song_id = $1 AND revision = $2`
+
+	var rev LyricsRevision
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, songID, revision).Scan(
+		&rev.Revision,
+		&rev.Author,
+		&rev.TextHash,
+		&rev.Text,
+		&rev.CreatedAt)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrNoRecordFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &rev, nil
+}
+
+// lockLyrics locks the song row until tx ends and returns its current lyrics,
+// so concurrent changes get consecutive revision numbers.
+func lockLyrics(ctx context.Context, tx *sql.Tx, songID int64) (string, error) {
+	var text string
+
+	err := tx.QueryRowContext(ctx, `SELECT text FROM songs WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, songID).Scan(&text)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return "", ErrNoRecordFound
+		default:
+			return "", err
+		}
+	}
+
+	return text, nil
+}
+
+// appendLyricsRevision records text as the next lyrics revision of a song
+// and drops the timings of the previous lyrics, which no longer match. The
+// song row must be locked with lockLyrics first.
+func appendLyricsRevision(ctx context.Context, tx *sql.Tx, songID int64, author, text string) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM lyric_timings WHERE song_id = $1`, songID)
+	if err != nil {
+		return err
+	}
+
+	query := `
+INSERT INTO lyrics_revisions (song_id, revision, author, text_hash, text)
+SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4
+FROM lyrics_revisions
+WHERE song_id = $1`
+
+	hash := sha256.Sum256([]byte(text))
+
+	_, err = tx.ExecContext(ctx, query, songID, author, hex.EncodeToString(hash[:]), text)
+	return err
+}
@@ -9,14 +9,47 @@ var (
 	ErrNoRecordFound = errors.New("no records found")
 	ErrEditConflict  = errors.New("edit conflict")
 	ErrAlreadyExists = errors.New("song of this group is already exists")
+
+	ErrDuplicateGroup  = errors.New("group with this name already exists")
+	ErrGroupHasSongs   = errors.New("group still has songs")
+	ErrGroupHasCredits = errors.New("group is still credited on songs")
+	ErrGroupHasAlbums  = errors.New("group still has albums")
+
+	ErrDuplicateAlbum = errors.New("album of this group already exists")
+	ErrTrackTaken     = errors.New("track number is already taken")
+
+	ErrPositionOutOfRange = errors.New("position is past the end of the playlist")
+
+	ErrDuplicateRelation     = errors.New("songs are already related this way")
+	ErrContradictoryRelation = errors.New("related song is already related back this way")
 )
 
 type Models struct {
-	Songs SongModel
+	Songs           SongModel
+	Groups          GroupModel
+	Albums          AlbumModel
+	LyricsRevisions LyricsRevisionModel
+	EnrichmentJobs  EnrichmentJobModel
+	Audit           AuditModel
+	Tags            TagModel
+	Playlists       PlaylistModel
+	SongLinks       SongLinkModel
+	SongRelations   SongRelationModel
+	Translations    LyricTranslationModel
 }
 
 func NewModels(db *sql.DB) Models {
 	return Models{
-		Songs: SongModel{DB: db},
+		Songs:           SongModel{DB: db},
+		Groups:          GroupModel{DB: db},
+		Albums:          AlbumModel{DB: db},
+		LyricsRevisions: LyricsRevisionModel{DB: db},
+		EnrichmentJobs:  EnrichmentJobModel{DB: db},
+		Audit:           AuditModel{DB: db},
+		Tags:            TagModel{DB: db},
+		Playlists:       PlaylistModel{DB: db},
+		SongLinks:       SongLinkModel{DB: db},
+		SongRelations:   SongRelationModel{DB: db},
+		Translations:    LyricTranslationModel{DB: db},
 	}
 }
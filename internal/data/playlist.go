@@ -0,0 +1,488 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"test_task/internal/validator"
+	"time"
+)
+
+// @Description Playlist data structure
+// @Schema
+type Playlist struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Items       int       `json:"items"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+}
+
+// @Description Playlist entry data structure; an entry whose song is in the trash keeps its position but has no song
+// @Schema
+type PlaylistItem struct {
+	Position int       `json:"position"`
+	AddedAt  time.Time `json:"added_at"`
+	SongID   int64     `json:"song_id"`
+	Song     *Song     `json:"song,omitempty"`
+	Trashed  bool      `json:"trashed,omitempty"`
+}
+
+type PlaylistModel struct {
+	DB *sql.DB
+}
+
+func ValidatePlaylist(v *validator.Validator, playlist *Playlist) {
+	v.Check(playlist.Name != "", "name", "must be provided")
+	v.Check(len(playlist.Name) <= 255, "name", "must not be more than 255 bytes long")
+	v.Check(len(playlist.Description) <= 4096, "description", "must not be more than 4096 bytes long")
+}
+
+func ValidatePosition(v *validator.Validator, key string, position int) {
+	v.Check(position > 0, key, "must be greater than zero")
+	v.Check(position <= 10_000, key, "must be a maximum of 10000")
+}
+
+const playlistColumns = `p.id, p.name, p.description,
+       (SELECT count(*) FROM playlist_items i WHERE i.playlist_id = p.id),
+       p.created_at, p.updated_at`
+
+func (playlist *Playlist) fields() []any {
+	return []any{
+		&playlist.ID,
+		&playlist.Name,
+		&playlist.Description,
+		&playlist.Items,
+		&playlist.CreatedAt,
+		&playlist.UpdatedAt,
+	}
+}
+
+var playlistSortColumns = map[string]string{
+	"id":         "p.id",
+	"name":       "lower(p.name)",
+	"updated_at": "p.updated_at",
+}
+
+func (m PlaylistModel) Insert(playlist *Playlist) error {
+	query := `
+INSERT INTO playlists (name, description)
+VALUES ($1, $2)
+RETURNING id, created_at, updated_at`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return m.DB.QueryRowContext(ctx, query, playlist.Name, playlist.Description).Scan(
+		&playlist.ID,
+		&playlist.CreatedAt,
+		&playlist.UpdatedAt)
+}
+
+func (m PlaylistModel) Get(id int64) (*Playlist, error) {
+	if id < 1 {
+		return nil, ErrNoRecordFound
+	}
+
+	query := `
+SELECT ` + playlistColumns + `
+FROM playlists p
+WHERE p.id = $1`
+
+	var playlist Playlist
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, id).Scan(playlist.fields()...)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrNoRecordFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &playlist, nil
+}
+
+func (m PlaylistModel) GetAll(name string, filters Filters) ([]*Playlist, Metadata, error) {
+	query := fmt.Sprintf(`
+SELECT count(*) OVER(), %s
+FROM playlists p
+WHERE (to_tsvector('simple', p.name) @@ plainto_tsquery('simple', $1) OR $1 = '')
+ORDER BY %s %s, p.id ASC
+LIMIT $2 OFFSET $3`,
+		playlistColumns, playlistSortColumns[filters.sortColumn()], filters.sortDirection())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, name, filters.limit(), filters.offset())
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	playlists := []*Playlist{}
+
+	for rows.Next() {
+		var playlist Playlist
+
+		err := rows.Scan(append([]any{&totalRecords}, playlist.fields()...)...)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		playlists = append(playlists, &playlist)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	metadata := calculateMetaData(totalRecords, filters.Page, filters.PageSize)
+
+	return playlists, metadata, nil
+}
+
+func (m PlaylistModel) Update(playlist *Playlist) error {
+	query := `
+UPDATE playlists
+SET name = $1, description = $2, updated_at = NOW()
+WHERE id = $3
+RETURNING updated_at`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, playlist.Name, playlist.Description, playlist.ID).Scan(&playlist.UpdatedAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrNoRecordFound
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m PlaylistModel) Delete(id int64) error {
+	if id < 1 {
+		return ErrNoRecordFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, `DELETE FROM playlists WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrNoRecordFound
+	}
+
+	return nil
+}
+
+// GetItems returns a page of the entries of a playlist in position order.
+// Entries whose song is in the trash are placeholders without the song, so
+// positions never skip.
+func (m PlaylistModel) GetItems(id int64, filters Filters) ([]*PlaylistItem, Metadata, error) {
+	query := `
+SELECT count(*) OVER(), i.position, i.added_at, ` + songColumns + `
+FROM ` + songTables + `
+JOIN playlist_items i ON i.song_id = s.id
+WHERE i.playlist_id = $1
+ORDER BY i.position
+LIMIT $2 OFFSET $3`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, id, filters.limit(), filters.offset())
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	items := []*PlaylistItem{}
+
+	for rows.Next() {
+		item := &PlaylistItem{Song: &Song{}}
+
+		err := rows.Scan(append([]any{&totalRecords, &item.Position, &item.AddedAt}, item.Song.fields()...)...)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		item.SongID = item.Song.ID
+		if item.Song.DeletedAt != nil {
+			item.Song = nil
+			item.Trashed = true
+		}
+
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	metadata := calculateMetaData(totalRecords, filters.Page, filters.PageSize)
+
+	return items, metadata, nil
+}
+
+// InsertItem puts the song of item into a playlist at item.Position, moving
+// the entries from there on one place down. Position 0 appends the song; the
+// position it ended up at is stored back in item.
+func (m PlaylistModel) InsertItem(id int64, item *PlaylistItem) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	last, err := lockPlaylist(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case item.Position == 0:
+		item.Position = last + 1
+	case item.Position > last+1:
+		return ErrPositionOutOfRange
+	}
+
+	_, err = tx.ExecContext(ctx, `
+UPDATE playlist_items
+SET position = position + 1
+WHERE playlist_id = $1 AND position >= $2`, id, item.Position)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+INSERT INTO playlist_items (playlist_id, song_id, position)
+VALUES ($1, $2, $3)
+RETURNING added_at`, id, item.SongID, item.Position).Scan(&item.AddedAt)
+	if err != nil {
+		return err
+	}
+
+	err = touchPlaylist(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// MoveItem moves the entry at position from to position to, shifting the
+// entries in between by one place.
+func (m PlaylistModel) MoveItem(id int64, from, to int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	last, err := lockPlaylist(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	if to > last {
+		return ErrPositionOutOfRange
+	}
+
+	var itemID int64
+
+	err = tx.QueryRowContext(ctx, `SELECT id FROM playlist_items WHERE playlist_id = $1 AND position = $2`, id, from).Scan(&itemID)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrNoRecordFound
+		default:
+			return err
+		}
+	}
+
+	if from == to {
+		return nil
+	}
+
+	query := `
+UPDATE playlist_items
+SET position = position + CASE WHEN $2 < $3 THEN -1 ELSE 1 END
+WHERE playlist_id = $1 AND position BETWEEN LEAST($2, $3) AND GREATEST($2, $3) AND position <> $2`
+
+	_, err = tx.ExecContext(ctx, query, id, from, to)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE playlist_items SET position = $1 WHERE id = $2`, to, itemID)
+	if err != nil {
+		return err
+	}
+
+	err = touchPlaylist(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RemoveItem takes the entry at position out of a playlist, moving the
+// entries after it one place up.
+func (m PlaylistModel) RemoveItem(id int64, position int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = lockPlaylist(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM playlist_items WHERE playlist_id = $1 AND position = $2`, id, position)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrNoRecordFound
+	}
+
+	_, err = tx.ExecContext(ctx, `
+UPDATE playlist_items
+SET position = position - 1
+WHERE playlist_id = $1 AND position > $2`, id, position)
+	if err != nil {
+		return err
+	}
+
+	err = touchPlaylist(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// lockPlaylist locks a playlist against concurrent reordering for the rest
+// of tx and returns its last position.
+func lockPlaylist(ctx context.Context, tx *sql.Tx, id int64) (int, error) {
+	var found bool
+
+	err := tx.QueryRowContext(ctx, `SELECT true FROM playlists WHERE id = $1 FOR UPDATE`, id).Scan(&found)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return 0, ErrNoRecordFound
+		default:
+			return 0, err
+		}
+	}
+
+	var last int
+
+	err = tx.QueryRowContext(ctx, `SELECT COALESCE(max(position), 0) FROM playlist_items WHERE playlist_id = $1`, id).Scan(&last)
+	if err != nil {
+		return 0, err
+	}
+
+	return last, nil
+}
+
+func touchPlaylist(ctx context.Context, tx *sql.Tx, id int64) error {
+	_, err := tx.ExecContext(ctx, `UPDATE playlists SET updated_at = NOW() WHERE id = $1`, id)
+	return err
+}
+
+// lockPlaylistsOf locks the playlists that have an entry for any of the songs
+// matched by the condition on songs s, in id order so that concurrent
+// callers can't deadlock.
+func lockPlaylistsOf(ctx context.Context, tx *sql.Tx, songs string, args ...any) ([]int64, error) {
+	query := `
+SELECT p.id
+FROM playlists p
+WHERE p.id IN (SELECT i.playlist_id FROM playlist_items i JOIN songs s ON s.id = i.song_id WHERE ` + songs + `)
+ORDER BY p.id
+FOR UPDATE`
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+
+	for rows.Next() {
+		var id int64
+
+		err := rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// compactPlaylists renumbers the entries of playlists from 1 without gaps,
+// keeping their order. The playlists must be locked first.
+func compactPlaylists(ctx context.Context, tx *sql.Tx, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query := `
+UPDATE playlist_items i
+SET position = r.position
+FROM (SELECT id, row_number() OVER (PARTITION BY playlist_id ORDER BY position) AS position
+      FROM playlist_items
+      WHERE playlist_id = ANY($1)) r
+WHERE i.id = r.id AND i.position <> r.position`
+
+	_, err := tx.ExecContext(ctx, query, pq.Array(ids))
+	return err
+}
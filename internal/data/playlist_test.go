@@ -0,0 +1,85 @@
+package data
+
+import (
+	"errors"
+	"slices"
+	"test_task/internal/testdb"
+	"testing"
+)
+
+// playlistSongs returns the ids of the songs in a playlist, in order.
+func playlistSongs(t *testing.T, models Models, id int64) []int64 {
+	t.Helper()
+
+	items, _, err := models.Playlists.GetItems(id, Filters{Page: 1, PageSize: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := make([]int64, len(items))
+	for i, item := range items {
+		if item.Position != i+1 {
+			t.Fatalf("item %d is at position %d", i+1, item.Position)
+		}
+		ids[i] = item.SongID
+	}
+
+	return ids
+}
+
+func TestPlaylistReordering(t *testing.T) {
+	models := NewModels(testdb.New(t))
+
+	a := insertSong(t, models, "Muse", "Uprising").ID
+	b := insertSong(t, models, "Muse", "Resistance").ID
+	c := insertSong(t, models, "Muse", "Undisclosed Desires").ID
+	d := insertSong(t, models, "Muse", "Exogenesis").ID
+
+	playlist := &Playlist{Name: "The Resistance"}
+
+	err := models.Playlists.Insert(playlist)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []int64{a, b, c} {
+		err := models.Playlists.InsertItem(playlist.ID, &PlaylistItem{SongID: id})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	steps := []struct {
+		name string
+		run  func() error
+		want []int64
+	}{
+		{"insert at 2", func() error {
+			return models.Playlists.InsertItem(playlist.ID, &PlaylistItem{SongID: d, Position: 2})
+		}, []int64{a, d, b, c}},
+		{"move 1 to 4", func() error { return models.Playlists.MoveItem(playlist.ID, 1, 4) }, []int64{d, b, c, a}},
+		{"move 3 to 1", func() error { return models.Playlists.MoveItem(playlist.ID, 3, 1) }, []int64{c, d, b, a}},
+		{"remove 2", func() error { return models.Playlists.RemoveItem(playlist.ID, 2) }, []int64{c, b, a}},
+	}
+
+	for _, step := range steps {
+		err := step.run()
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+
+		if got := playlistSongs(t, models, playlist.ID); !slices.Equal(got, step.want) {
+			t.Fatalf("%s: got songs %v; want %v", step.name, got, step.want)
+		}
+	}
+
+	err = models.Playlists.MoveItem(playlist.ID, 1, 4)
+	if !errors.Is(err, ErrPositionOutOfRange) {
+		t.Errorf("move past the end: got %v; want %v", err, ErrPositionOutOfRange)
+	}
+
+	err = models.Playlists.InsertItem(playlist.ID, &PlaylistItem{SongID: d, Position: 5})
+	if !errors.Is(err, ErrPositionOutOfRange) {
+		t.Errorf("insert past the end: got %v; want %v", err, ErrPositionOutOfRange)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/lib/pq"
+	"strings"
 	"test_task/internal/validator"
 	"time"
 )
@@ -16,35 +17,225 @@ type Song struct {
 	ID        int64     `json:"id"`
 	Song      string    `json:"song"`
 	Group     string    `json:"group"`
+	GroupID   int64     `json:"group_id"`
+	Credits   Credits   `json:"credits,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	Version   int32     `json:"version"`
 
-	Release string `json:"releaseDate"`
-	Text    string `json:"text"`
-	Link    string `json:"link"`
+	Release     string `json:"releaseDate"`
+	ReleaseDate *Date  `json:"release_date,omitempty"`
+	Text        string `json:"text"`
+	Link        string `json:"link"`
+
+	AlbumID     int64  `json:"album_id,omitempty"`
+	Album       string `json:"album,omitempty"`
+	TrackNumber int    `json:"track_number,omitempty"`
+
+	Links  SongLinks `json:"links,omitempty"`
+	Genres []string  `json:"genres,omitempty"`
+	Tags   []string  `json:"tags,omitempty"`
+
+	Sources    DetailSources `json:"sources,omitempty"`
+	Provenance []Provenance  `json:"provenance,omitempty"`
+
+	EnrichmentStatus string `json:"enrichment_status"`
+	EnrichmentError  string `json:"enrichment_error,omitempty"`
+
+	// LyricsEditedAt is set once users write the lyrics themselves; fetched
+	// details no longer replace them then.
+	LyricsEditedAt *time.Time `json:"lyrics_edited_at,omitempty"`
+
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+const (
+	EnrichmentPending = "pending"
+	EnrichmentDone    = "done"
+	EnrichmentFailed  = "failed"
+)
+
+// SongFilter holds the optional filters of a songs listing.
+type SongFilter struct {
+	Song    string
+	Group   string
+	GroupID int64
+	Release string
+	Text    string
+	Link    string
+	Album   string
+
+	ReleasedFrom *Date
+	ReleasedTo   *Date
+	Year         int
+
+	Tags    []string
+	Genres  []string
+	TagMode string
+
+	Platforms []string
+
+	Filters
+}
+
+// songColumns are the columns every song query selects from songTables, in
+// the order of Song.fields.
+// songLinkColumn is the link of a song: its primary platform link, or else
+// the fetched link whose platform isn't recognised.
+const songLinkColumn = `COALESCE((SELECT l.url FROM song_links l WHERE l.song_id = s.id AND l.is_primary), s.link)`
+
+const songColumns = `s.id, s.created_at, s.updated_at, s.version, s.song_name, s.group_id, g.name,
+       COALESCE((SELECT jsonb_agg(jsonb_build_object('group_id', cg.id, 'group', cg.name, 'role', sa.role) ORDER BY sa.role, lower(cg.name))
+                 FROM song_artists sa JOIN groups cg ON cg.id = sa.group_id WHERE sa.song_id = s.id), '[]'),
+       s.release, s.release_date, s.text, ` + songLinkColumn + `, COALESCE(s.album_id, 0), COALESCE(a.title, ''), COALESCE(s.track_number, 0),
+       COALESCE((SELECT jsonb_agg(jsonb_build_object('platform', l.platform, 'url', l.url, 'primary', l.is_primary) ORDER BY l.is_primary DESC, l.platform)
+                 FROM song_links l WHERE l.song_id = s.id), '[]'),
+       ARRAY(SELECT t.name FROM song_tags st JOIN tags t ON t.id = st.tag_id WHERE st.song_id = s.id AND t.kind = 'genre' ORDER BY lower(t.name)),
+       ARRAY(SELECT t.name FROM song_tags st JOIN tags t ON t.id = st.tag_id WHERE st.song_id = s.id AND t.kind = 'tag' ORDER BY lower(t.name)),
+       s.detail_sources, s.enrichment_status, s.enrichment_error, s.lyrics_edited_at, s.deleted_at`
+
+const songTables = `songs s
+JOIN groups g ON g.id = s.group_id
+LEFT JOIN albums a ON a.id = s.album_id`
+
+// songSortColumns maps the public sort keys to SQL expressions.
+var songSortColumns = map[string]string{
+	"id":      "s.id",
+	"song":    "s.song_name",
+	"group":   "g.name",
+	"release": "s.release_date",
+	"text":    "s.text",
+	"link":    songLinkColumn,
+}
+
+// songOrderBy returns the ORDER BY list for the requested sort. Sorting by
+// album keeps the tracks of an album in track order.
+func songOrderBy(f Filters) string {
+	direction := f.sortDirection()
+
+	switch column := f.sortColumn(); column {
+	case "album":
+		return fmt.Sprintf("a.title %s NULLS LAST, s.track_number %s, s.id ASC", direction, direction)
+	default:
+		return fmt.Sprintf("%s %s NULLS LAST, s.id ASC", songSortColumns[column], direction)
+	}
+}
+
+func (song *Song) fields() []any {
+	return []any{
+		&song.ID,
+		&song.CreatedAt,
+		&song.UpdatedAt,
+		&song.Version,
+		&song.Song,
+		&song.GroupID,
+		&song.Group,
+		&song.Credits,
+		&song.Release,
+		&song.ReleaseDate,
+		&song.Text,
+		&song.Link,
+		&song.AlbumID,
+		&song.Album,
+		&song.TrackNumber,
+		&song.Links,
+		pq.Array(&song.Genres),
+		pq.Array(&song.Tags),
+		&song.Sources,
+		&song.EnrichmentStatus,
+		&song.EnrichmentError,
+		&song.LyricsEditedAt,
+		&song.DeletedAt,
+	}
 }
 
 type SongModel struct {
-	DB *sql.DB
+	DB    *sql.DB
+	actor Actor
+}
+
+// Actor identifies who makes a change through a model.
+type Actor struct {
+	Name      string
+	RequestID string
+}
+
+// As returns a copy of the model that attributes its changes to actor.
+func (s SongModel) As(actor Actor) SongModel {
+	s.actor = actor
+	return s
+}
+
+func (s SongModel) author() string {
+	if s.actor.Name == "" {
+		return "system"
+	}
+
+	return s.actor.Name
 }
 
 func ValidateSong(v *validator.Validator, song *Song) {
 	v.Check(song.Song != "", "song", "must be provided")
 	v.Check(song.Group != "", "group", "must be provided")
+
+	ValidateCredits(v, song.Group, song.Credits)
 }
 
+func ValidateSongFilter(v *validator.Validator, f SongFilter) {
+	if f.ReleasedFrom != nil && f.ReleasedTo != nil {
+		v.Check(!f.ReleasedTo.Before(f.ReleasedFrom.Time), "released_to", "must not be before released_from")
+	}
+	v.Check(f.Year == 0 || (f.Year >= 1000 && f.Year <= 9999), "year", "must be a four-digit year")
+	v.Check(validator.PermittedValue(f.TagMode, TagMatchAny, TagMatchAll), "tag_mode", "must be and or or")
+	for _, platform := range f.Platforms {
+		v.Check(validator.PermittedValue(platform, Platforms...), "platform", "must be one of youtube, spotify, apple_music, bandcamp")
+	}
+
+	ValidateFilters(v, f.Filters)
+}
+
+// Insert adds a song with its credits, creating its groups if they don't
+// exist yet. A pending song is queued for enrichment in the same statement,
+// so a crash can't leave it without an enrichment job.
 func (s SongModel) Insert(song *Song) error {
 	query := `
-INSERT INTO songs (song_name, group_name, release, text, link)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, created_at, updated_at`
+WITH grp AS (
+    INSERT INTO groups (name)
+    VALUES ($2)
+    ON CONFLICT (lower(name)) DO UPDATE SET name = groups.name
+    RETURNING id, name),
+song AS (
+    INSERT INTO songs (song_name, group_id, release, text, link, enrichment_status, credits_key)
+    SELECT $1, grp.id, $3, $4, $5, $6, $7 FROM grp
+    RETURNING id, created_at, updated_at, version, enrichment_status),
+job AS (
+    INSERT INTO enrichment_jobs (song_id)
+    SELECT id FROM song WHERE enrichment_status = 'pending')
+SELECT song.id, song.created_at, song.updated_at, song.version, grp.id, grp.name
+FROM song, grp`
 
-	args := []any{song.Song, song.Group, song.Release, song.Text, song.Link}
+	if song.EnrichmentStatus == "" {
+		song.EnrichmentStatus = EnrichmentPending
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	err := s.DB.QueryRowContext(ctx, query, args...).Scan(&song.ID, &song.CreatedAt, &song.UpdatedAt)
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = upsertCreditGroups(ctx, tx, song.Credits)
+	if err != nil {
+		return err
+	}
+
+	args := []any{song.Song, NormaliseGroupName(song.Group), song.Release, song.Text, song.Link, song.EnrichmentStatus,
+		creditsKey(song.Credits)}
+
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&song.ID, &song.CreatedAt, &song.UpdatedAt, &song.Version, &song.GroupID, &song.Group)
 	if err != nil {
 		var pqErr *pq.Error
 		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
@@ -53,27 +244,87 @@ RETURNING id, created_at, updated_at`
 		return err
 	}
 
-	return nil
+	err = insertCredits(ctx, tx, song.ID, song.Credits)
+	if err != nil {
+		return err
+	}
+
+	after, err := songSnapshot(ctx, tx, song.ID)
+	if err != nil {
+		return err
+	}
+
+	err = s.recordAudit(ctx, tx, song.ID, AuditInsert, nil, after)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
+// Update saves the name, group and lyrics of a song, creating the group if
+// needed and deleting the previous group if nothing else refers to it. It
+// fails with ErrEditConflict unless the stored song still has song.Version.
+// Changed lyrics are recorded as a new revision and count as edited by users,
+// unless they are cleared, which hands them back to the provider.
 func (s SongModel) Update(song *Song) error {
 	query := `
-UPDATE songs
-SET song_name = $1, group_name = $2,updated_at = NOW()
-WHERE id = $3
-RETURNING updated_at`
+WITH grp AS (
+    INSERT INTO groups (name)
+    VALUES ($2)
+    ON CONFLICT (lower(name)) DO UPDATE SET name = groups.name
+    RETURNING id, name)
+UPDATE songs s
+SET song_name = $1, group_id = grp.id, text = $3, version = s.version + 1, updated_at = NOW(),
+    lyrics_edited_at = CASE WHEN $3 = s.text THEN s.lyrics_edited_at WHEN $3 = '' THEN NULL ELSE NOW() END
+FROM grp
+WHERE s.id = $4 AND s.version = $5
+RETURNING s.updated_at, s.version, grp.id, grp.name`
 
 	args := []any{
 		song.Song,
-		song.Group,
+		NormaliseGroupName(song.Group),
+		song.Text,
 		song.ID,
+		song.Version,
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	err := s.DB.QueryRowContext(ctx, query, args...).Scan(
-		&song.UpdatedAt)
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	oldText, err := lockLyrics(ctx, tx, song.ID)
+	if err != nil {
+		if errors.Is(err, ErrNoRecordFound) {
+			return ErrEditConflict
+		}
+		return err
+	}
+
+	var oldGroupID int64
+
+	err = tx.QueryRowContext(ctx, `SELECT group_id FROM songs WHERE id = $1`, song.ID).Scan(&oldGroupID)
+	if err != nil {
+		return err
+	}
+
+	err = s.audited(ctx, tx, song.ID, AuditUpdate, func() error {
+		err := tx.QueryRowContext(ctx, query, args...).Scan(
+			&song.UpdatedAt,
+			&song.Version,
+			&song.GroupID,
+			&song.Group)
+		if err != nil || song.Text == oldText {
+			return err
+		}
+
+		return appendLyricsRevision(ctx, tx, song.ID, s.author(), song.Text)
+	})
 
 	if err != nil {
 		var pqErr *pq.Error
@@ -86,26 +337,452 @@ RETURNING updated_at`
 			return err
 		}
 	}
+
+	if song.GroupID != oldGroupID {
+		err = deleteOrphanGroup(ctx, tx, oldGroupID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// SetCredits replaces the credited groups of a song, creating the groups if
+// needed. It fails with ErrAlreadyExists if the song would then duplicate
+// another song of its group with the same featured groups.
+func (s SongModel) SetCredits(id int64, credits []Credit) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = lockLyrics(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	err = upsertCreditGroups(ctx, tx, credits)
+	if err != nil {
+		return err
+	}
+
+	err = s.audited(ctx, tx, id, AuditUpdate, func() error {
+		_, err := tx.ExecContext(ctx, `DELETE FROM song_artists WHERE song_id = $1`, id)
+		if err != nil {
+			return err
+		}
+
+		err = insertCredits(ctx, tx, id, credits)
+		if err != nil {
+			return err
+		}
+
+		query := `
+UPDATE songs
+SET credits_key = $1, version = version + 1, updated_at = NOW()
+WHERE id = $2`
+
+		_, err = tx.ExecContext(ctx, query, creditsKey(credits), id)
+		return err
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return ErrAlreadyExists
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RestoreLyrics makes the text of a lyrics revision the current lyrics of a
+// song, which is itself recorded as a new revision.
+func (s SongModel) RestoreLyrics(id int64, revision int) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	oldText, err := lockLyrics(ctx, tx, id)
+	if err != nil {
+		return "", err
+	}
+
+	var text string
+
+	err = tx.QueryRowContext(ctx, `SELECT text FROM lyrics_revisions WHERE song_id = $1 AND revision = $2`, id, revision).Scan(&text)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return "", ErrNoRecordFound
+		default:
+			return "", err
+		}
+	}
+
+	if text != oldText {
+		err = s.audited(ctx, tx, id, AuditUpdate, func() error {
+			_, err := tx.ExecContext(ctx, `UPDATE songs SET text = $1, lyrics_edited_at = NOW(), version = version + 1, updated_at = NOW() WHERE id = $2`, text, id)
+			if err != nil {
+				return err
+			}
+
+			return appendLyricsRevision(ctx, tx, id, s.author(), text)
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return text, tx.Commit()
+}
+
+// SetDetails stores fetched details of a song together with the raw provider
+// answers they came from, and marks its enrichment as done. Changed lyrics
+// are recorded as a new revision; lyrics edited by users, synced ones
+// included, are kept.
+func (s SongModel) SetDetails(id int64, detail *SongDetail) error {
+	query := `
+UPDATE songs
+SET release = $1, release_date = $2, text = $3, link = $4, detail_sources = $5,
+    enrichment_status = $6, enrichment_error = '', details_fetched_at = NOW(), resync_failures = 0, resync_retry_at = NULL,
+    version = version + 1, updated_at = NOW()
+WHERE id = $7`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	oldText, err := lockLyrics(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	var edited bool
+
+	err = tx.QueryRowContext(ctx, `SELECT lyrics_edited_at IS NOT NULL FROM songs WHERE id = $1`, id).Scan(&edited)
+	if err != nil {
+		return err
+	}
+
+	text := detail.Text
+	if edited {
+		text = oldText
+	}
+
+	// A link to a known platform goes to the song's links below; songs.link
+	// only keeps links that can't go there.
+	link := strings.TrimSpace(detail.Link)
+	platform := PlatformOf(link)
+	if platform != "" {
+		link = ""
+	}
+
+	args := []any{detail.Release, ParseReleaseDate(detail.Release), text, link, detail.Sources, EnrichmentDone, id}
+
+	err = s.audited(ctx, tx, id, AuditUpdate, func() error {
+		_, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		if text != oldText {
+			err = appendLyricsRevision(ctx, tx, id, s.author(), text)
+			if err != nil {
+				return err
+			}
+		}
+
+		// A fetched link to a known platform is kept among the song's
+		// links, unless the song already has one there.
+		if platform != "" {
+			query := `
+INSERT INTO song_links (song_id, platform, url, is_primary)
+VALUES ($1, $2, $3, NOT EXISTS (SELECT 1 FROM song_links WHERE song_id = $1 AND is_primary))
+ON CONFLICT (song_id, platform) DO NOTHING`
+
+			_, err = tx.ExecContext(ctx, query, id, platform, strings.TrimSpace(detail.Link))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = replaceProvenance(ctx, tx, id, detail.Provenance)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// replaceProvenance replaces the stored provider answers of a song.
+func replaceProvenance(ctx context.Context, tx *sql.Tx, id int64, provenance []Provenance) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM song_provenance WHERE song_id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range provenance {
+		query := `
+INSERT INTO song_provenance (song_id, provider, fetched_at, http_status, payload)
+VALUES ($1, $2, $3, $4, $5)`
+
+		_, err = tx.ExecContext(ctx, query, id, p.Provider, p.FetchedAt, p.HTTPStatus, []byte(p.Payload))
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-func (s SongModel) GetAll(song, group, release, text, link string, filters Filters) ([]*Song, Metadata, error) {
+// GetProvenance returns the raw provider answers the details of a song were
+// built from.
+func (s SongModel) GetProvenance(id int64) ([]Provenance, error) {
+	query := `
+SELECT provider, fetched_at, http_status, payload
+FROM song_provenance
+WHERE song_id = $1
+ORDER BY id`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := s.DB.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	provenance := []Provenance{}
+
+	for rows.Next() {
+		var p Provenance
+		var payload []byte
+
+		err := rows.Scan(&p.Provider, &p.FetchedAt, &p.HTTPStatus, &payload)
+		if err != nil {
+			return nil, err
+		}
+
+		p.Payload = payload
+
+		provenance = append(provenance, p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return provenance, nil
+}
+
+// GetStale returns up to limit enriched songs whose details were fetched
+// more than maxAge ago, oldest first. Songs whose last resync failed are left
+// out until their backoff has passed.
+func (s SongModel) GetStale(maxAge time.Duration, limit int) ([]*Song, error) {
+	query := `
+SELECT ` + songColumns + `
+FROM ` + songTables + `
+WHERE s.enrichment_status = 'done'
+AND s.deleted_at IS NULL
+AND s.details_fetched_at < NOW() - make_interval(secs => $1)
+AND (s.resync_retry_at IS NULL OR s.resync_retry_at <= NOW())
+ORDER BY s.details_fetched_at, s.id
+LIMIT $2`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := s.DB.QueryContext(ctx, query, maxAge.Seconds(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var songs []*Song
+
+	for rows.Next() {
+		var song Song
+
+		err := rows.Scan(song.fields()...)
+		if err != nil {
+			return nil, err
+		}
+
+		songs = append(songs, &song)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return songs, nil
+}
+
+// SetDetailsChecked records that the details of a song were re-fetched and
+// found unchanged, keeping the provider answers they now come from. The
+// song's version only changes if the details are now credited to other
+// providers.
+func (s SongModel) SetDetailsChecked(id int64, detail *SongDetail) error {
+	query := `
+UPDATE songs
+SET details_fetched_at = NOW(), resync_failures = 0, resync_retry_at = NULL,
+    version = version + CASE WHEN detail_sources = $2 THEN 0 ELSE 1 END,
+    updated_at = CASE WHEN detail_sources = $2 THEN updated_at ELSE NOW() END,
+    detail_sources = $2
+WHERE id = $1`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = s.audited(ctx, tx, id, AuditUpdate, func() error {
+		_, err := tx.ExecContext(ctx, query, id, detail.Sources)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	err = replaceProvenance(ctx, tx, id, detail.Provenance)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// SetResyncFailed puts off the next resync of a song after a failed one. The
+// delay starts at backoff and doubles with each consecutive failure, up to
+// 1024 times backoff.
+func (s SongModel) SetResyncFailed(id int64, backoff time.Duration) error {
+	query := `
+UPDATE songs
+SET resync_failures = resync_failures + 1,
+    resync_retry_at = NOW() + make_interval(secs => $2 * 2 ^ LEAST(resync_failures, 10))
+WHERE id = $1`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := s.DB.ExecContext(ctx, query, id, backoff.Seconds())
+	return err
+}
+
+// SetEnrichmentFailed marks the enrichment of a song as failed with the reason.
+func (s SongModel) SetEnrichmentFailed(id int64, reason string) error {
+	query := `
+UPDATE songs
+SET enrichment_status = $1, enrichment_error = $2, version = version + 1, updated_at = NOW()
+WHERE id = $3`
+
+	return s.exec(id, AuditUpdate, query, EnrichmentFailed, reason, id)
+}
+
+// exec runs a query that changes the song id and records the change in the
+// audit log. It returns ErrNoRecordFound if the query changed nothing.
+func (s SongModel) exec(id int64, action string, query string, args ...any) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = s.audited(ctx, tx, id, action, func() error {
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return ErrNoRecordFound
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s SongModel) GetAll(filter SongFilter) ([]*Song, Metadata, error) {
 	query := fmt.Sprintf(`
-SELECT count(*) OVER(), id, created_at, updated_at, song_name, group_name, release, text, link
-FROM songs
-WHERE (to_tsvector('simple', song_name) @@ plainto_tsquery('simple', $1) OR $1 = '')
-AND (to_tsvector('simple', group_name) @@ plainto_tsquery('simple', $2) OR $2 = '')
-AND (to_tsvector('simple', release) @@ plainto_tsquery('simple', $3) OR $3 = '')
-AND (to_tsvector('simple', text) @@ plainto_tsquery('simple', $4) OR $4 = '')
-AND (to_tsvector('simple', link) @@ plainto_tsquery('simple', $5) OR $5 = '')
-ORDER BY %s %s, id ASC
-LIMIT $6 OFFSET $7`,
-		filters.sortColumn(), filters.sortDirection())
+SELECT count(*) OVER(), %s
+FROM %s
+WHERE s.deleted_at IS NULL
+AND (to_tsvector('simple', s.song_name) @@ plainto_tsquery('simple', $1) OR $1 = '')
+AND (to_tsvector('simple', g.name) @@ plainto_tsquery('simple', $2) OR $2 = '' OR EXISTS (
+    SELECT 1 FROM song_artists sa JOIN groups cg ON cg.id = sa.group_id
+    WHERE sa.song_id = s.id AND to_tsvector('simple', cg.name) @@ plainto_tsquery('simple', $2)))
+AND (s.group_id = $3 OR $3 = 0 OR EXISTS (
+    SELECT 1 FROM song_artists sa WHERE sa.song_id = s.id AND sa.group_id = $3))
+AND (to_tsvector('simple', s.release) @@ plainto_tsquery('simple', $4) OR $4 = '')
+AND (to_tsvector('simple', s.text) @@ plainto_tsquery('simple', $5) OR $5 = '')
+AND (to_tsvector('simple', %s) @@ plainto_tsquery('simple', $6) OR $6 = '')
+AND (to_tsvector('simple', a.title) @@ plainto_tsquery('simple', $7) OR $7 = '')
+AND (s.release_date >= $8 OR $8 IS NULL)
+AND (s.release_date <= $9 OR $9 IS NULL)
+AND (EXTRACT(YEAR FROM s.release_date) = $10 OR $10 = 0)
+AND (cardinality($13::TEXT[]) = 0 OR (
+    SELECT count(*) FROM song_tags st JOIN tags t ON t.id = st.tag_id
+    WHERE st.song_id = s.id AND t.kind = 'tag' AND lower(t.name) = ANY($13)
+) >= CASE WHEN $15 THEN cardinality($13) ELSE 1 END)
+AND (cardinality($14::TEXT[]) = 0 OR (
+    SELECT count(*) FROM song_tags st JOIN tags t ON t.id = st.tag_id
+    WHERE st.song_id = s.id AND t.kind = 'genre' AND lower(t.name) = ANY($14)
+) >= CASE WHEN $15 THEN cardinality($14) ELSE 1 END)
+AND (cardinality($16::TEXT[]) = 0 OR (
+    SELECT count(*) FROM song_links l
+    WHERE l.song_id = s.id AND l.platform = ANY($16)
+) = cardinality($16))
+ORDER BY %s
+LIMIT $11 OFFSET $12`,
+		songColumns, songTables, songLinkColumn, songOrderBy(filter.Filters))
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	args := []any{song, group, release, text, link, filters.limit(), filters.offset()}
+	args := []any{filter.Song, filter.Group, filter.GroupID, filter.Release, filter.Text, filter.Link, filter.Album,
+		filter.ReleasedFrom, filter.ReleasedTo, filter.Year, filter.limit(), filter.offset(),
+		pq.Array(tagKeys(filter.Tags)), pq.Array(tagKeys(filter.Genres)), filter.TagMode == TagMatchAll,
+		pq.Array(platformKeys(filter.Platforms))}
 
 	rows, err := s.DB.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -124,16 +801,7 @@ LIMIT $6 OFFSET $7`,
 	for rows.Next() {
 		song := &Song{}
 
-		err := rows.Scan(
-			&totalRecords,
-			&song.ID,
-			&song.CreatedAt,
-			&song.UpdatedAt,
-			&song.Song,
-			&song.Group,
-			&song.Release,
-			&song.Text,
-			&song.Link)
+		err := rows.Scan(append([]any{&totalRecords}, song.fields()...)...)
 
 		if err != nil {
 			return nil, Metadata{}, err
@@ -146,7 +814,7 @@ LIMIT $6 OFFSET $7`,
 		return nil, Metadata{}, err
 	}
 
-	metadata := calculateMetaData(totalRecords, filters.Page, filters.PageSize)
+	metadata := calculateMetaData(totalRecords, filter.Page, filter.PageSize)
 
 	return songs, metadata, nil
 
@@ -160,24 +828,16 @@ func (s SongModel) Get(id int64) (*Song, error) {
 	}
 
 	query := `
-SELECT id, created_at, updated_at, song_name, group_name, release, text, link 
-FROM songs
-WHERE id = $1`
+SELECT ` + songColumns + `
+FROM ` + songTables + `
+WHERE s.id = $1 AND s.deleted_at IS NULL`
 
 	var song Song
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	err := s.DB.QueryRowContext(ctx, query, id).Scan(
-		&song.ID,
-		&song.CreatedAt,
-		&song.UpdatedAt,
-		&song.Song,
-		&song.Group,
-		&song.Release,
-		&song.Text,
-		&song.Link)
+	err := s.DB.QueryRowContext(ctx, query, id).Scan(song.fields()...)
 
 	if err != nil {
 		switch {
@@ -191,72 +851,239 @@ WHERE id = $1`
 	return &song, nil
 }
 
-func (s SongModel) GetLyrics(id int64, page int, pageSize int) ([]string, Metadata, error) {
+// GetLyrics returns the full lyrics text of a song.
+func (s SongModel) GetLyrics(id int64) (string, error) {
 	if id < 1 {
-		return nil, Metadata{}, ErrNoRecordFound
+		return "", ErrNoRecordFound
 	}
 
 	query := `
-SELECT text 
+SELECT text
 FROM songs
-WHERE id = $1`
+WHERE id = $1 AND deleted_at IS NULL`
 
-	var song Song
+	var text string
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	err := s.DB.QueryRowContext(ctx, query, id).Scan(
-		&song.Text)
+	err := s.DB.QueryRowContext(ctx, query, id).Scan(&text)
 
 	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
-			return nil, Metadata{}, ErrNoRecordFound
+			return "", ErrNoRecordFound
 		default:
-			return nil, Metadata{}, err
+			return "", err
 		}
 	}
 
-	verses := splitTextIntoVerses(song.Text)
+	return text, nil
+}
+
+// lockSong locks a song that isn't in the trash until tx ends.
+func lockSong(ctx context.Context, tx *sql.Tx, id int64) error {
+	var found bool
 
-	totalRecords := len(verses)
-	start := (page - 1) * pageSize
-	end := start + pageSize
-	if end > totalRecords {
-		end = totalRecords
+	err := tx.QueryRowContext(ctx, `SELECT true FROM songs WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id).Scan(&found)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrNoRecordFound
+		default:
+			return err
+		}
 	}
 
-	metadata := calculateMetaData(totalRecords, page, pageSize)
+	return nil
+}
+
+// touchSong bumps the version of a song after a change to something that
+// belongs to it, so that its ETag changes too.
+func touchSong(ctx context.Context, tx *sql.Tx, id int64) error {
+	_, err := tx.ExecContext(ctx, `UPDATE songs SET version = version + 1, updated_at = NOW() WHERE id = $1`, id)
+	return err
+}
+
+// Exists reports whether a song is stored, counting songs in the trash.
+func (s SongModel) Exists(id int64) (bool, error) {
+	query := `
+SELECT EXISTS (SELECT 1 FROM songs WHERE id = $1)`
+
+	var exists bool
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := s.DB.QueryRowContext(ctx, query, id).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
 
-	return verses[start:end], metadata, nil
+	return exists, nil
 }
 
-func (s *SongModel) Delete(id int64) error {
+// Delete moves a song to the trash. It stays there, hidden from Get and
+// GetAll, until it is restored or purged. It fails with ErrEditConflict
+// unless the stored song still has the given version.
+func (s SongModel) Delete(id int64, version int32) error {
 	if id < 1 {
 		return ErrNoRecordFound
 	}
 
 	query := `
-DELETE FROM songs
-WHERE id = $1`
+UPDATE songs
+SET deleted_at = NOW(), version = version + 1
+WHERE id = $1 AND version = $2 AND deleted_at IS NULL`
+
+	err := s.exec(id, AuditDelete, query, id, version)
+	if errors.Is(err, ErrNoRecordFound) {
+		return ErrEditConflict
+	}
+
+	return err
+}
+
+// GetTrash returns the songs in the trash, most recently deleted first.
+func (s SongModel) GetTrash(filters Filters) ([]*Song, Metadata, error) {
+	query := `
+SELECT count(*) OVER(), ` + songColumns + `
+FROM ` + songTables + `
+WHERE s.deleted_at IS NOT NULL
+ORDER BY s.deleted_at DESC, s.id DESC
+LIMIT $1 OFFSET $2`
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	result, err := s.DB.ExecContext(ctx, query, id)
+	rows, err := s.DB.QueryContext(ctx, query, filters.limit(), filters.offset())
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	songs := []*Song{}
+
+	for rows.Next() {
+		song := &Song{}
+
+		err := rows.Scan(append([]any{&totalRecords}, song.fields()...)...)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		songs = append(songs, song)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	return songs, calculateMetaData(totalRecords, filters.Page, filters.PageSize), nil
+}
+
+// Restore takes a song out of the trash. It fails if another song with the
+// same name and group, or on the same album track, was added meanwhile. A
+// song that was still waiting for its details is queued for enrichment again.
+func (s SongModel) Restore(id int64) error {
+	if id < 1 {
+		return ErrNoRecordFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	rowsAffected, err := result.RowsAffected()
+	err = s.audited(ctx, tx, id, AuditRestore, func() error {
+		result, err := tx.ExecContext(ctx, `
+UPDATE songs
+SET deleted_at = NULL, version = version + 1, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return ErrNoRecordFound
+		}
+
+		return nil
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		switch {
+		case errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "unique_album_track":
+			return ErrTrackTaken
+		case errors.As(err, &pqErr) && pqErr.Code == "23505":
+			return ErrAlreadyExists
+		default:
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+INSERT INTO enrichment_jobs (song_id)
+SELECT id FROM songs WHERE id = $1 AND enrichment_status = 'pending'
+AND NOT EXISTS (SELECT 1 FROM enrichment_jobs WHERE song_id = $1 AND status = 'pending')`, id)
 	if err != nil {
 		return err
 	}
 
-	if rowsAffected == 0 {
-		return ErrNoRecordFound
+	return tx.Commit()
+}
+
+// Purge permanently deletes the songs that have been in the trash for longer
+// than retention, recording their last state in the audit log, and returns
+// how many there were. Playlists that had them are renumbered without gaps.
+func (s SongModel) Purge(retention time.Duration) (int64, error) {
+	query := `
+WITH purged AS (
+    DELETE FROM songs
+    WHERE deleted_at < NOW() - make_interval(secs => $1)
+    RETURNING id, to_jsonb(songs) - 'updated_at' - 'version' - 'details_fetched_at' - 'resync_failures' - 'resync_retry_at' - 'lyrics_edited_at' AS row)
+INSERT INTO audit_log (song_id, action, actor, request_id, changes)
+SELECT id, $2, $3, $4, (SELECT jsonb_object_agg(key, jsonb_build_object('before', value, 'after', NULL)) FROM jsonb_each(row))
+FROM purged`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
 	}
+	defer tx.Rollback()
 
-	return nil
+	playlists, err := lockPlaylistsOf(ctx, tx, `s.deleted_at < NOW() - make_interval(secs => $1)`, retention.Seconds())
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := tx.ExecContext(ctx, query, retention.Seconds(), AuditPurge, s.author(), s.actor.RequestID)
+	if err != nil {
+		return 0, err
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	err = compactPlaylists(ctx, tx, playlists)
+	if err != nil {
+		return 0, err
+	}
+
+	return purged, tx.Commit()
 }
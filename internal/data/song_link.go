@@ -0,0 +1,260 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"test_task/internal/validator"
+	"time"
+)
+
+const (
+	PlatformYouTube    = "youtube"
+	PlatformSpotify    = "spotify"
+	PlatformAppleMusic = "apple_music"
+	PlatformBandcamp   = "bandcamp"
+)
+
+// platformHosts lists the hosts, including their subdomains, that links of a
+// platform may point to.
+var platformHosts = map[string][]string{
+	PlatformYouTube:    {"youtube.com", "youtu.be"},
+	PlatformSpotify:    {"spotify.com"},
+	PlatformAppleMusic: {"music.apple.com", "itunes.apple.com"},
+	PlatformBandcamp:   {"bandcamp.com"},
+}
+
+var Platforms = []string{PlatformYouTube, PlatformSpotify, PlatformAppleMusic, PlatformBandcamp}
+
+// @Description Link to a song on a streaming platform
+// @Schema
+type SongLink struct {
+	Platform string `json:"platform"`
+	URL      string `json:"url"`
+	Primary  bool   `json:"primary"`
+}
+
+// SongLinks are the platform links of a song as read from the database,
+// primary link first.
+type SongLinks []SongLink
+
+func (l *SongLinks) Scan(src any) error {
+	b, ok := src.([]byte)
+	if !ok {
+		return errors.New("song links: unsupported type")
+	}
+
+	return json.Unmarshal(b, l)
+}
+
+func ValidateSongLink(v *validator.Validator, link *SongLink) {
+	if !validator.PermittedValue(link.Platform, Platforms...) {
+		v.AddError("platform", "must be one of youtube, spotify, apple_music, bandcamp")
+		return
+	}
+
+	v.Check(link.URL != "", "url", "must be provided")
+	v.Check(len(link.URL) <= 2048, "url", "must not be more than 2048 bytes long")
+
+	u, err := url.Parse(link.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		v.AddError("url", "must be an absolute http(s) URL")
+		return
+	}
+
+	v.Check(matchesPlatform(u.Hostname(), link.Platform), "url", "must point to "+link.Platform)
+}
+
+func matchesPlatform(host, platform string) bool {
+	host = strings.ToLower(host)
+
+	for _, h := range platformHosts[platform] {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PlatformOf returns the platform a link points to, or "" if it isn't a link
+// to a known platform.
+func PlatformOf(link string) string {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return ""
+	}
+
+	for _, platform := range Platforms {
+		if matchesPlatform(u.Hostname(), platform) {
+			return platform
+		}
+	}
+
+	return ""
+}
+
+// platformKeys de-duplicates platform names for matching.
+func platformKeys(platforms []string) []string {
+	seen := make(map[string]bool, len(platforms))
+	keys := []string{}
+
+	for _, p := range platforms {
+		p = strings.TrimSpace(p)
+		if p == "" || seen[p] {
+			continue
+		}
+
+		seen[p] = true
+		keys = append(keys, p)
+	}
+
+	return keys
+}
+
+// LinkAfter returns what the link of song becomes once a fetched link is
+// stored by SetDetails. A song with platform links keeps its primary link,
+// since the fetched one can only join the others.
+func (song *Song) LinkAfter(fetched string) string {
+	if len(song.Links) > 0 {
+		return song.Link
+	}
+
+	return strings.TrimSpace(fetched)
+}
+
+type SongLinkModel struct {
+	DB    *sql.DB
+	actor Actor
+}
+
+// As returns a copy of the model that attributes its changes to actor.
+func (m SongLinkModel) As(actor Actor) SongLinkModel {
+	m.actor = actor
+	return m
+}
+
+// songs returns the song model that records changes in the audit log on
+// behalf of the model's actor.
+func (m SongLinkModel) songs() SongModel {
+	return SongModel{DB: m.DB, actor: m.actor}
+}
+
+// Set adds or replaces the link of a song on a platform and bumps the song's
+// version. The first link of a song becomes its primary link; making another
+// link primary demotes the previous one.
+func (m SongLinkModel) Set(songID int64, link *SongLink) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var hasPrimary bool
+
+	err = tx.QueryRowContext(ctx, `
+SELECT EXISTS (SELECT 1 FROM song_links WHERE song_id = s.id AND is_primary AND platform <> $2)
+FROM songs s
+WHERE s.id = $1 AND s.deleted_at IS NULL
+FOR UPDATE OF s`, songID, link.Platform).Scan(&hasPrimary)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrNoRecordFound
+		default:
+			return err
+		}
+	}
+
+	if !hasPrimary {
+		link.Primary = true
+	}
+
+	query := `
+INSERT INTO song_links (song_id, platform, url, is_primary)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (song_id, platform) DO UPDATE
+SET url = EXCLUDED.url, is_primary = EXCLUDED.is_primary, updated_at = NOW()`
+
+	err = m.songs().audited(ctx, tx, songID, AuditUpdate, func() error {
+		if link.Primary {
+			_, err := tx.ExecContext(ctx, `UPDATE song_links SET is_primary = false, updated_at = NOW() WHERE song_id = $1 AND is_primary`, songID)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := tx.ExecContext(ctx, query, songID, link.Platform, link.URL, link.Primary)
+		if err != nil {
+			return err
+		}
+
+		return touchSong(ctx, tx, songID)
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Remove deletes the link of a song on a platform and bumps the song's
+// version. If it was the primary link, the oldest remaining link takes its
+// place. Songs in the trash are left as they are.
+func (m SongLinkModel) Remove(songID int64, platform string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Locking the song serialises this with Set, so the two can't both
+	// promote a link to primary.
+	err = lockSong(ctx, tx, songID)
+	if err != nil {
+		return err
+	}
+
+	err = m.songs().audited(ctx, tx, songID, AuditUpdate, func() error {
+		var wasPrimary bool
+
+		err := tx.QueryRowContext(ctx, `
+DELETE FROM song_links
+WHERE song_id = $1 AND platform = $2
+RETURNING is_primary`, songID, platform).Scan(&wasPrimary)
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				return ErrNoRecordFound
+			default:
+				return err
+			}
+		}
+
+		if wasPrimary {
+			_, err = tx.ExecContext(ctx, `
+UPDATE song_links
+SET is_primary = true, updated_at = NOW()
+WHERE id = (SELECT id FROM song_links WHERE song_id = $1 ORDER BY created_at, id LIMIT 1)`, songID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return touchSong(ctx, tx, songID)
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,74 @@
+package data
+
+import (
+	"test_task/internal/testdb"
+	"testing"
+)
+
+// primaryLink returns the platform of the primary link of a song.
+func primaryLink(t *testing.T, models Models, id int64) string {
+	t.Helper()
+
+	var primary string
+
+	for _, link := range getSong(t, models, id).Links {
+		if !link.Primary {
+			continue
+		}
+
+		if primary != "" {
+			t.Fatalf("song %d has primary links on %s and %s", id, primary, link.Platform)
+		}
+
+		primary = link.Platform
+	}
+
+	return primary
+}
+
+func TestSongLinkPrimary(t *testing.T) {
+	models := NewModels(testdb.New(t))
+	song := insertSong(t, models, "Muse", "Uprising")
+
+	links := []*SongLink{
+		{Platform: PlatformYouTube, URL: "https://www.youtube.com/watch?v=w8KQmps-Sog"},
+		{Platform: PlatformSpotify, URL: "https://open.spotify.com/track/4VqPOruhp5EdPBeR92t6lQ"},
+	}
+
+	for _, link := range links {
+		err := models.SongLinks.Set(song.ID, link)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := primaryLink(t, models, song.ID); got != PlatformYouTube {
+		t.Fatalf("got primary link %q; want the first link, %q", got, PlatformYouTube)
+	}
+
+	err := models.SongLinks.Set(song.ID, &SongLink{
+		Platform: PlatformBandcamp,
+		URL:      "https://muse.bandcamp.com/track/uprising",
+		Primary:  true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := primaryLink(t, models, song.ID); got != PlatformBandcamp {
+		t.Fatalf("got primary link %q; want %q", got, PlatformBandcamp)
+	}
+
+	err = models.SongLinks.Remove(song.ID, PlatformBandcamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := primaryLink(t, models, song.ID); got != PlatformYouTube {
+		t.Errorf("got primary link %q after removing the primary; want the oldest, %q", got, PlatformYouTube)
+	}
+
+	if got := getSong(t, models, song.ID).Version; got != song.Version+4 {
+		t.Errorf("got version %d after four link changes; want %d", got, song.Version+4)
+	}
+}
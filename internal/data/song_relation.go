@@ -0,0 +1,263 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/lib/pq"
+	"test_task/internal/validator"
+	"time"
+)
+
+// A relation reads from the song it belongs to: a song is a cover_of,
+// remix_of, live_version_of or sample_of the related song.
+const (
+	RelationCoverOf       = "cover_of"
+	RelationRemixOf       = "remix_of"
+	RelationLiveVersionOf = "live_version_of"
+	RelationSampleOf      = "sample_of"
+)
+
+var RelationTypes = []string{RelationCoverOf, RelationRemixOf, RelationLiveVersionOf, RelationSampleOf}
+
+// InverseRelations names each relation as seen from the related song.
+var InverseRelations = map[string]string{
+	RelationCoverOf:       "covered_by",
+	RelationRemixOf:       "remixed_by",
+	RelationLiveVersionOf: "live_versions",
+	RelationSampleOf:      "sampled_by",
+}
+
+type SongRelationModel struct {
+	DB    *sql.DB
+	actor Actor
+}
+
+// As returns a copy of the model that attributes its changes to actor.
+func (m SongRelationModel) As(actor Actor) SongRelationModel {
+	m.actor = actor
+	return m
+}
+
+// songs returns the song model that records changes in the audit log on
+// behalf of the model's actor.
+func (m SongRelationModel) songs() SongModel {
+	return SongModel{DB: m.DB, actor: m.actor}
+}
+
+func ValidateRelation(v *validator.Validator, songID, relatedID int64, relation string) {
+	v.Check(validator.PermittedValue(relation, RelationTypes...), "type", "must be one of cover_of, remix_of, live_version_of, sample_of")
+	v.Check(relatedID > 0, "song_id", "must be provided")
+	v.Check(relatedID != songID, "song_id", "must not be the song itself")
+}
+
+// Link relates a song to another one and bumps the versions of both. Both
+// songs must exist outside the trash, and the related song must not already
+// be related back to the song the same way.
+func (m SongRelationModel) Link(songID, relatedID int64, relation string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Locking both songs keeps linking A to B and B to A at the same time
+	// from both passing the inverse check below.
+	err = lockSongPair(ctx, tx, songID, relatedID)
+	if err != nil {
+		return err
+	}
+
+	var inverse bool
+
+	err = tx.QueryRowContext(ctx, `
+SELECT EXISTS (
+	SELECT 1 FROM song_relations
+	WHERE song_id = $1 AND related_song_id = $2 AND type = $3
+)`, relatedID, songID, relation).Scan(&inverse)
+	if err != nil {
+		return err
+	}
+
+	if inverse {
+		return ErrContradictoryRelation
+	}
+
+	query := `
+INSERT INTO song_relations (song_id, related_song_id, type)
+VALUES ($1, $2, $3)`
+
+	err = m.auditedPair(ctx, tx, songID, relatedID, func() error {
+		_, err := tx.ExecContext(ctx, query, songID, relatedID, relation)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+				return ErrDuplicateRelation
+			}
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Unlink removes a relation from a song to another one and bumps the
+// versions of both. Both songs must exist outside the trash.
+func (m SongRelationModel) Unlink(songID, relatedID int64, relation string) error {
+	query := `
+DELETE FROM song_relations
+WHERE song_id = $1 AND related_song_id = $2 AND type = $3`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = lockSongPair(ctx, tx, songID, relatedID)
+	if err != nil {
+		return err
+	}
+
+	err = m.auditedPair(ctx, tx, songID, relatedID, func() error {
+		result, err := tx.ExecContext(ctx, query, songID, relatedID, relation)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return ErrNoRecordFound
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// lockSongPair locks two songs outside the trash in id order, so that
+// transactions locking the same pair can't deadlock.
+func lockSongPair(ctx context.Context, tx *sql.Tx, a, b int64) error {
+	if a > b {
+		a, b = b, a
+	}
+
+	for _, id := range []int64{a, b} {
+		err := lockSong(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// auditedPair runs fn, which changes how two songs relate inside tx, bumps
+// the versions of both and records the change in the audit log of each.
+func (m SongRelationModel) auditedPair(ctx context.Context, tx *sql.Tx, a, b int64, fn func() error) error {
+	songs := m.songs()
+
+	return songs.audited(ctx, tx, a, AuditUpdate, func() error {
+		return songs.audited(ctx, tx, b, AuditUpdate, func() error {
+			err := fn()
+			if err != nil {
+				return err
+			}
+
+			for _, id := range []int64{a, b} {
+				err = touchSong(ctx, tx, id)
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	})
+}
+
+// GetRelated returns the songs related to a song, grouped by relation. The
+// relations other songs have to this one are grouped under their inverse
+// names. Every relation is present, possibly with no songs. Songs in the
+// trash have no related songs to list.
+func (m SongRelationModel) GetRelated(songID int64) (map[string][]*Song, error) {
+	query := `
+SELECT r.type, true, ` + songColumns + `
+FROM ` + songTables + `
+JOIN song_relations r ON r.related_song_id = s.id
+WHERE r.song_id = $1 AND s.deleted_at IS NULL
+UNION ALL
+SELECT r.type, false, ` + songColumns + `
+FROM ` + songTables + `
+JOIN song_relations r ON r.song_id = s.id
+WHERE r.related_song_id = $1 AND s.deleted_at IS NULL
+ORDER BY 1, 2, 3`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var exists bool
+
+	err := m.DB.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM songs WHERE id = $1 AND deleted_at IS NULL)`, songID).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, ErrNoRecordFound
+	}
+
+	rows, err := m.DB.QueryContext(ctx, query, songID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	related := make(map[string][]*Song, 2*len(RelationTypes))
+	for relation, inverse := range InverseRelations {
+		related[relation] = []*Song{}
+		related[inverse] = []*Song{}
+	}
+
+	for rows.Next() {
+		var relation string
+		var outgoing bool
+		var song Song
+
+		err := rows.Scan(append([]any{&relation, &outgoing}, song.fields()...)...)
+		if err != nil {
+			return nil, err
+		}
+
+		if !outgoing {
+			relation = InverseRelations[relation]
+		}
+
+		related[relation] = append(related[relation], &song)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return related, nil
+}
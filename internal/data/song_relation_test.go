@@ -0,0 +1,67 @@
+package data
+
+import (
+	"errors"
+	"test_task/internal/testdb"
+	"testing"
+)
+
+func TestSongRelations(t *testing.T) {
+	models := NewModels(testdb.New(t))
+	original := insertSong(t, models, "Prince", "Nothing Compares 2 U")
+	cover := insertSong(t, models, "Sinéad O'Connor", "Nothing Compares 2 U")
+
+	err := models.SongRelations.Link(cover.ID, original.ID, RelationCoverOf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, song := range []*Song{original, cover} {
+		if got := getSong(t, models, song.ID).Version; got != song.Version+1 {
+			t.Errorf("song %d: got version %d after linking; want %d", song.ID, got, song.Version+1)
+		}
+	}
+
+	err = models.SongRelations.Link(original.ID, cover.ID, RelationCoverOf)
+	if !errors.Is(err, ErrContradictoryRelation) {
+		t.Errorf("Link of the inverse relation: got %v; want %v", err, ErrContradictoryRelation)
+	}
+
+	err = models.SongRelations.Link(cover.ID, original.ID, RelationCoverOf)
+	if !errors.Is(err, ErrDuplicateRelation) {
+		t.Errorf("Link of the same relation again: got %v; want %v", err, ErrDuplicateRelation)
+	}
+
+	related, err := models.SongRelations.GetRelated(original.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inverse := InverseRelations[RelationCoverOf]
+	if got := related[inverse]; len(got) != 1 || got[0].ID != cover.ID {
+		t.Errorf("got %s %v; want only song %d", inverse, got, cover.ID)
+	}
+
+	if got := related[RelationCoverOf]; len(got) != 0 {
+		t.Errorf("got %s %v; want none", RelationCoverOf, got)
+	}
+
+	err = models.Songs.Delete(cover.ID, cover.Version+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	related, err = models.SongRelations.GetRelated(original.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := related[inverse]; len(got) != 0 {
+		t.Errorf("got %s %v after trashing the cover; want none", inverse, got)
+	}
+
+	err = models.SongRelations.Unlink(cover.ID, original.ID, RelationCoverOf)
+	if !errors.Is(err, ErrNoRecordFound) {
+		t.Errorf("Unlink from a trashed song: got %v; want %v", err, ErrNoRecordFound)
+	}
+}
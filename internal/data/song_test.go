@@ -0,0 +1,177 @@
+package data
+
+import (
+	"errors"
+	"test_task/internal/testdb"
+	"testing"
+)
+
+// insertSong adds a song that needs no enrichment.
+func insertSong(t *testing.T, models Models, group, name string) *Song {
+	t.Helper()
+
+	song := &Song{Song: name, Group: group, EnrichmentStatus: EnrichmentDone}
+
+	err := models.Songs.Insert(song)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return song
+}
+
+// getSong returns the stored song id, failing the test if it can't be read.
+func getSong(t *testing.T, models Models, id int64) *Song {
+	t.Helper()
+
+	song, err := models.Songs.Get(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return song
+}
+
+func TestSongDeleteAndRestore(t *testing.T) {
+	models := NewModels(testdb.New(t))
+	song := insertSong(t, models, "Muse", "Uprising")
+
+	err := models.Songs.Delete(song.ID, song.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = models.Songs.Get(song.ID)
+	if !errors.Is(err, ErrNoRecordFound) {
+		t.Fatalf("Get of a trashed song: got %v; want %v", err, ErrNoRecordFound)
+	}
+
+	trash, _, err := models.Songs.GetTrash(Filters{Page: 1, PageSize: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(trash) != 1 || trash[0].ID != song.ID {
+		t.Fatalf("got %d songs in the trash; want only song %d", len(trash), song.ID)
+	}
+
+	err = models.Songs.Restore(song.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored := getSong(t, models, song.ID)
+	if restored.Version != song.Version+2 {
+		t.Errorf("got version %d after delete and restore; want %d", restored.Version, song.Version+2)
+	}
+
+	err = models.Songs.Restore(song.ID)
+	if !errors.Is(err, ErrNoRecordFound) {
+		t.Errorf("Restore of a song not in the trash: got %v; want %v", err, ErrNoRecordFound)
+	}
+}
+
+func TestSongVersionConflict(t *testing.T) {
+	models := NewModels(testdb.New(t))
+	song := insertSong(t, models, "Muse", "Uprising")
+	stale := song.Version
+
+	song.Text = "Paranoia is in bloom"
+
+	err := models.Songs.Update(song)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	song.Version = stale
+	song.Text = "The PR transmissions will resume"
+
+	err = models.Songs.Update(song)
+	if !errors.Is(err, ErrEditConflict) {
+		t.Errorf("Update with a stale version: got %v; want %v", err, ErrEditConflict)
+	}
+
+	err = models.Songs.Delete(song.ID, stale)
+	if !errors.Is(err, ErrEditConflict) {
+		t.Errorf("Delete with a stale version: got %v; want %v", err, ErrEditConflict)
+	}
+
+	if got := getSong(t, models, song.ID).Text; got != "Paranoia is in bloom" {
+		t.Errorf("got text %q; want the first update to stay", got)
+	}
+}
+
+func TestSongAuditDiff(t *testing.T) {
+	models := NewModels(testdb.New(t))
+	song := insertSong(t, models, "Muse", "Uprising")
+
+	song.Song = "Uprising (Live)"
+
+	err := models.Songs.As(Actor{Name: "alice", RequestID: "req-1"}).Update(song)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, _, err := models.Audit.GetAll(AuditFilter{SongID: song.ID, Filters: Filters{Page: 1, PageSize: 100}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("got %d audit entries; want 2", len(entries))
+	}
+
+	update, insert := entries[0], entries[1]
+
+	if insert.Action != AuditInsert || update.Action != AuditUpdate {
+		t.Fatalf("got actions %q, %q; want %q, %q", insert.Action, update.Action, AuditInsert, AuditUpdate)
+	}
+
+	if update.Actor != "alice" || update.RequestID != "req-1" {
+		t.Errorf("got actor %q, request %q; want alice, req-1", update.Actor, update.RequestID)
+	}
+
+	change, ok := update.Changes["song_name"]
+	if !ok {
+		t.Fatalf("song_name is missing from the changes %v", update.Changes)
+	}
+
+	if string(change.Before) != `"Uprising"` || string(change.After) != `"Uprising (Live)"` {
+		t.Errorf("got song_name %s -> %s; want \"Uprising\" -> \"Uprising (Live)\"", change.Before, change.After)
+	}
+
+	if _, ok := update.Changes["text"]; ok {
+		t.Errorf("unchanged text is in the changes %v", update.Changes)
+	}
+}
+
+func TestSetTimedLyricsBumpsVersionOnNewTimings(t *testing.T) {
+	models := NewModels(testdb.New(t))
+	song := insertSong(t, models, "Muse", "Uprising")
+	text := "Paranoia is in bloom\nThe PR transmissions will resume"
+
+	err := models.Songs.SetTimedLyrics(song.ID, text, Timings{1: 1000, 2: 4000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	version := getSong(t, models, song.ID).Version
+
+	err = models.Songs.SetTimedLyrics(song.ID, text, Timings{1: 1000, 2: 4000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := getSong(t, models, song.ID).Version; got != version {
+		t.Errorf("got version %d after saving the same timings; want %d", got, version)
+	}
+
+	err = models.Songs.SetTimedLyrics(song.ID, text, Timings{1: 1500, 2: 4000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := getSong(t, models, song.ID).Version; got != version+1 {
+		t.Errorf("got version %d after moving a timing; want %d", got, version+1)
+	}
+}
@@ -0,0 +1,237 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"test_task/internal/validator"
+	"time"
+)
+
+// Tags come in two kinds: genres and free-form tags. Both are attached to
+// songs the same way and are unique per kind regardless of case.
+const (
+	KindTag   = "tag"
+	KindGenre = "genre"
+)
+
+// Tag matching modes of a songs listing: with TagMatchAny a song needs one of
+// the requested tags (and one of the requested genres), with TagMatchAll it
+// needs all of them.
+const (
+	TagMatchAny = "or"
+	TagMatchAll = "and"
+)
+
+// @Description Tag or genre with the number of songs it is attached to
+// @Schema
+type Tag struct {
+	Kind  string `json:"kind"`
+	Name  string `json:"name"`
+	Songs int    `json:"songs"`
+}
+
+type TagModel struct {
+	DB    *sql.DB
+	actor Actor
+}
+
+// As returns a copy of the model that attributes its changes to actor.
+func (m TagModel) As(actor Actor) TagModel {
+	m.actor = actor
+	return m
+}
+
+// songs returns the song model that records changes in the audit log on
+// behalf of the model's actor.
+func (m TagModel) songs() SongModel {
+	return SongModel{DB: m.DB, actor: m.actor}
+}
+
+// NormaliseTagName trims a tag name and collapses inner whitespace.
+func NormaliseTagName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+func ValidateTag(v *validator.Validator, tag *Tag) {
+	v.Check(validator.PermittedValue(tag.Kind, KindTag, KindGenre), "kind", "must be tag or genre")
+	v.Check(tag.Name != "", "name", "must be provided")
+	v.Check(len(tag.Name) <= 64, "name", "must not be more than 64 bytes long")
+}
+
+// tagKeys lowercases and de-duplicates tag names for matching.
+func tagKeys(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	keys := []string{}
+
+	for _, name := range names {
+		key := strings.ToLower(NormaliseTagName(name))
+		if key == "" || seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// Attach attaches a tag of the given kind to a song, creating the tag if it
+// doesn't exist yet, and bumps the song's version. Attaching a tag twice is
+// not an error and leaves the song as it is.
+func (m TagModel) Attach(songID int64, kind, name string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = lockSong(ctx, tx, songID)
+	if err != nil {
+		return err
+	}
+
+	// Upserting the tag locks its row, so a concurrent Detach can't drop it
+	// until this song is attached to it.
+	query := `
+WITH tag AS (
+    INSERT INTO tags (kind, name)
+    VALUES ($2, $3)
+    ON CONFLICT (kind, lower(name)) DO UPDATE SET name = tags.name
+    RETURNING id)
+INSERT INTO song_tags (song_id, tag_id)
+SELECT $1, tag.id FROM tag
+ON CONFLICT DO NOTHING`
+
+	err = m.songs().audited(ctx, tx, songID, AuditUpdate, func() error {
+		result, err := tx.ExecContext(ctx, query, songID, kind, NormaliseTagName(name))
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return nil
+		}
+
+		return touchSong(ctx, tx, songID)
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Detach removes a tag of the given kind from a song and bumps the song's
+// version. A tag that is no longer attached to any song is dropped. Songs in
+// the trash are left as they are.
+func (m TagModel) Detach(songID int64, kind, name string) error {
+	query := `
+DELETE FROM song_tags st
+USING tags t
+WHERE st.tag_id = t.id
+AND st.song_id = $1 AND t.kind = $2 AND lower(t.name) = lower($3)
+RETURNING t.id`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = lockSong(ctx, tx, songID)
+	if err != nil {
+		return err
+	}
+
+	var tagID int64
+
+	err = m.songs().audited(ctx, tx, songID, AuditUpdate, func() error {
+		err := tx.QueryRowContext(ctx, query, songID, kind, NormaliseTagName(name)).Scan(&tagID)
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				return ErrNoRecordFound
+			default:
+				return err
+			}
+		}
+
+		return touchSong(ctx, tx, songID)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Locking the tag first waits for a concurrent Attach that has just
+	// upserted it; the following statement then sees its new attachment.
+	_, err = tx.ExecContext(ctx, `SELECT id FROM tags WHERE id = $1 FOR UPDATE`, tagID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM tags WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM song_tags WHERE tag_id = $1)`, tagID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetAll lists tags, optionally of one kind, with the number of songs each
+// one is attached to, most used first. Songs in the trash are not counted.
+func (m TagModel) GetAll(kind string, filters Filters) ([]*Tag, Metadata, error) {
+	query := `
+SELECT count(*) OVER(), t.kind, t.name, count(s.id)
+FROM tags t
+LEFT JOIN song_tags st ON st.tag_id = t.id
+LEFT JOIN songs s ON s.id = st.song_id AND s.deleted_at IS NULL
+WHERE (t.kind = $1 OR $1 = '')
+GROUP BY t.id
+ORDER BY count(s.id) DESC, lower(t.name), t.kind
+LIMIT $2 OFFSET $3`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, kind, filters.limit(), filters.offset())
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	tags := []*Tag{}
+
+	for rows.Next() {
+		var tag Tag
+
+		err := rows.Scan(&totalRecords, &tag.Kind, &tag.Name, &tag.Songs)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		tags = append(tags, &tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	metadata := calculateMetaData(totalRecords, filters.Page, filters.PageSize)
+
+	return tags, metadata, nil
+}
@@ -0,0 +1,65 @@
+package data
+
+import (
+	"errors"
+	"test_task/internal/testdb"
+	"testing"
+)
+
+func TestTagAttachAndDetach(t *testing.T) {
+	models := NewModels(testdb.New(t))
+	song := insertSong(t, models, "Muse", "Uprising")
+
+	err := models.Tags.Attach(song.ID, KindTag, "Anthem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	version := getSong(t, models, song.ID).Version
+	if version != song.Version+1 {
+		t.Errorf("got version %d after attaching a tag; want %d", version, song.Version+1)
+	}
+
+	err = models.Tags.Attach(song.ID, KindTag, "anthem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := getSong(t, models, song.ID).Version; got != version {
+		t.Errorf("got version %d after attaching the tag again; want %d", got, version)
+	}
+
+	err = models.Tags.Detach(song.ID, KindTag, "Anthem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tags, _, err := models.Tags.GetAll(KindTag, Filters{Page: 1, PageSize: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tags) != 0 {
+		t.Errorf("got %d tags after detaching the last use; want none", len(tags))
+	}
+}
+
+func TestTagDetachFromTrashedSong(t *testing.T) {
+	models := NewModels(testdb.New(t))
+	song := insertSong(t, models, "Muse", "Uprising")
+
+	err := models.Tags.Attach(song.ID, KindGenre, "Rock")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = models.Songs.Delete(song.ID, song.Version+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = models.Tags.Detach(song.ID, KindGenre, "Rock")
+	if !errors.Is(err, ErrNoRecordFound) {
+		t.Errorf("Detach from a trashed song: got %v; want %v", err, ErrNoRecordFound)
+	}
+}
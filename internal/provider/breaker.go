@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"test_task/internal/data"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("provider is unavailable (circuit breaker is open)")
+
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half-open"
+)
+
+type BreakerConfig struct {
+	FailureThreshold int
+	Cooldown         time.Duration
+}
+
+// Breaker is a circuit breaker around a provider. After FailureThreshold
+// consecutive failures it fails fast with ErrCircuitOpen for
+// Cooldown, then lets a single probe request through to decide whether to
+// close again.
+type Breaker struct {
+	next SongDetailProvider
+	cfg  BreakerConfig
+
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func NewBreaker(p SongDetailProvider, cfg BreakerConfig) *Breaker {
+	if cfg.FailureThreshold < 1 {
+		cfg.FailureThreshold = 1
+	}
+
+	return &Breaker{next: p, cfg: cfg, state: BreakerClosed}
+}
+
+func (b *Breaker) Name() string {
+	return b.next.Name()
+}
+
+// State returns the current breaker state: closed, open or half-open.
+func (b *Breaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && time.Since(b.openedAt) >= b.cfg.Cooldown {
+		return BreakerHalfOpen
+	}
+
+	return b.state
+}
+
+func (b *Breaker) Fetch(ctx context.Context, song, group string) (*data.SongDetail, error) {
+	if !b.allow() {
+		return nil, ErrCircuitOpen
+	}
+
+	detail, err := b.next.Fetch(ctx, song, group)
+	b.record(ctx, err)
+
+	return detail, err
+}
+
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.cfg.Cooldown {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		return true
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+func (b *Breaker) record(ctx context.Context, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+
+	// The caller giving up, or the call being throttled before it reached
+	// the provider, says nothing about the provider's health.
+	if err != nil && (ctx.Err() != nil || errors.Is(err, ErrThrottled)) {
+		return
+	}
+
+	if !isFailure(err) {
+		b.state = BreakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.cfg.FailureThreshold {
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// isFailure reports whether err means the provider itself is misbehaving, as
+// opposed to a regular "not found" or a rejected request.
+func isFailure(err error) bool {
+	if err == nil || errors.Is(err, ErrNotFound) {
+		return false
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	return true
+}
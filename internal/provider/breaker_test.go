@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBreakerIgnoresThrottling(t *testing.T) {
+	stub := &stubProvider{name: "stub", err: ErrThrottled}
+	breaker := NewBreaker(stub, BreakerConfig{FailureThreshold: 1, Cooldown: time.Hour})
+
+	for range 3 {
+		_, err := breaker.Fetch(context.Background(), "Uprising", "Muse")
+		if !errors.Is(err, ErrThrottled) {
+			t.Fatalf("got %v, want ErrThrottled", err)
+		}
+	}
+
+	if state := breaker.State(); state != BreakerClosed {
+		t.Errorf("state = %s, want closed", state)
+	}
+}
+
+func TestOpenBreakerSpendsNoRateLimitTokens(t *testing.T) {
+	stub := &stubProvider{name: "stub", err: &StatusError{Provider: "stub", StatusCode: 503}}
+	limiter := NewRateLimiter(stub, RateLimitConfig{Rate: 0.001, Burst: 2})
+	breaker := NewBreaker(limiter, BreakerConfig{FailureThreshold: 1, Cooldown: time.Hour})
+
+	_, err := breaker.Fetch(context.Background(), "Uprising", "Muse")
+	if err == nil {
+		t.Fatal("expected the provider's failure")
+	}
+
+	for range 3 {
+		_, err = breaker.Fetch(context.Background(), "Uprising", "Muse")
+		if !errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("got %v, want ErrCircuitOpen", err)
+		}
+	}
+
+	if stub.calls != 1 {
+		t.Errorf("provider got %d calls, want 1", stub.calls)
+	}
+
+	stub.err = nil
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err = limiter.Fetch(ctx, "Uprising", "Muse")
+	if err != nil {
+		t.Errorf("the limiter has no token left: %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"test_task/internal/data"
+	"time"
+)
+
+type CacheConfig struct {
+	Size        int
+	TTL         time.Duration
+	NegativeTTL time.Duration
+}
+
+type CacheStats struct {
+	Hits         uint64 `json:"hits"`
+	Misses       uint64 `json:"misses"`
+	NegativeHits uint64 `json:"negative_hits"`
+	Entries      int    `json:"entries"`
+}
+
+type cacheEntry struct {
+	key       string
+	detail    *data.SongDetail
+	expiresAt time.Time
+}
+
+// Cache is a TTL'd LRU cache in front of a provider, keyed by the normalised
+// (group, song) pair. "Not found" answers are cached as well, for NegativeTTL.
+// Partial details are never cached, so the next fetch asks the failed
+// providers again.
+type Cache struct {
+	next SongDetailProvider
+	cfg  CacheConfig
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+
+	hits         atomic.Uint64
+	misses       atomic.Uint64
+	negativeHits atomic.Uint64
+}
+
+func NewCache(p SongDetailProvider, cfg CacheConfig) *Cache {
+	return &Cache{
+		next:    p,
+		cfg:     cfg,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (c *Cache) Name() string {
+	return c.next.Name()
+}
+
+// Fetch answers from the cache when possible. Contexts marked with
+// BypassCache always reach the provider, and the fresh answer replaces the
+// cached one.
+func (c *Cache) Fetch(ctx context.Context, song, group string) (*data.SongDetail, error) {
+	key := detailKey(song, group)
+
+	if entry, ok := c.get(key); ok && !bypassCache(ctx) {
+		if entry.detail == nil {
+			c.negativeHits.Add(1)
+			return nil, ErrNotFound
+		}
+		c.hits.Add(1)
+		detail := *entry.detail
+		return &detail, nil
+	}
+
+	c.misses.Add(1)
+
+	detail, err := c.next.Fetch(ctx, song, group)
+	switch {
+	case err == nil && !detail.Partial:
+		stored := *detail
+		c.set(key, &stored, c.cfg.TTL)
+	case errors.Is(err, ErrNotFound):
+		c.set(key, nil, c.cfg.NegativeTTL)
+	}
+
+	return detail, err
+}
+
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	entries := len(c.entries)
+	c.mu.Unlock()
+
+	return CacheStats{
+		Hits:         c.hits.Load(),
+		Misses:       c.misses.Load(),
+		NegativeHits: c.negativeHits.Load(),
+		Entries:      entries,
+	}
+}
+
+func (c *Cache) get(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := el.Value.(*cacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(el)
+	return entry, true
+}
+
+func (c *Cache) set(key string, detail *data.SongDetail, ttl time.Duration) {
+	if ttl <= 0 || c.cfg.Size <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &cacheEntry{key: key, detail: detail, expiresAt: time.Now().Add(ttl)}
+
+	if el, ok := c.entries[key]; ok {
+		el.Value = entry
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+
+	for c.order.Len() > c.cfg.Size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+type bypassCacheKey struct{}
+
+// BypassCache returns a context whose fetches skip cached answers.
+func BypassCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassCacheKey{}, true)
+}
+
+func bypassCache(ctx context.Context) bool {
+	bypass, _ := ctx.Value(bypassCacheKey{}).(bool)
+	return bypass
+}
+
+func detailKey(song, group string) string {
+	return normalise(group) + "\x00" + normalise(song)
+}
+
+// normalise lower-cases s and collapses runs of whitespace, so "Muse" and
+// " muse " share a cache entry.
+func normalise(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"test_task/internal/data"
+	"testing"
+	"time"
+)
+
+// stubProvider answers with its detail, or fails with err while it is set,
+// and counts the calls it gets.
+type stubProvider struct {
+	name   string
+	detail data.SongDetail
+	err    error
+	calls  int
+}
+
+func (p *stubProvider) Name() string {
+	return p.name
+}
+
+func (p *stubProvider) Fetch(ctx context.Context, song, group string) (*data.SongDetail, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	detail := p.detail
+	return &detail, nil
+}
+
+func TestCacheSkipsPartialDetails(t *testing.T) {
+	first := &stubProvider{name: "first", detail: data.SongDetail{Release: "16.07.2006", Text: "Ooh baby"}, err: ErrCircuitOpen}
+	second := &stubProvider{name: "second", detail: data.SongDetail{Link: "https://example.com"}}
+
+	cache := NewCache(NewChain(first, second), CacheConfig{Size: 10, TTL: time.Hour, NegativeTTL: time.Minute})
+
+	detail, err := cache.Fetch(context.Background(), "Supermassive Black Hole", "Muse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !detail.Partial || detail.Link != "https://example.com" || detail.Text != "" {
+		t.Errorf("detail = %+v, want partial details from the second provider", detail)
+	}
+
+	first.err = nil
+
+	detail, err = cache.Fetch(context.Background(), "Supermassive Black Hole", "Muse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.calls != 2 {
+		t.Errorf("first provider got %d calls, want 2", first.calls)
+	}
+	if detail.Partial || detail.Text != "Ooh baby" || detail.Link != "https://example.com" {
+		t.Errorf("detail = %+v, want complete details", detail)
+	}
+
+	_, err = cache.Fetch(context.Background(), "Supermassive Black Hole", "Muse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.calls != 2 || cache.Stats().Hits != 1 {
+		t.Errorf("complete details were not cached: %d calls, stats %+v", first.calls, cache.Stats())
+	}
+}
+
+func TestChainNotFound(t *testing.T) {
+	first := &stubProvider{name: "first", err: ErrNotFound}
+	second := &stubProvider{name: "second", err: ErrNotFound}
+
+	_, err := NewChain(first, second).Fetch(context.Background(), "Uprising", "Muse")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v, want ErrNotFound", err)
+	}
+
+	second.err = ErrThrottled
+
+	_, err = NewChain(first, second).Fetch(context.Background(), "Uprising", "Muse")
+	if errors.Is(err, ErrNotFound) || !errors.Is(err, ErrThrottled) {
+		t.Errorf("got %v, want ErrThrottled", err)
+	}
+}
@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"test_task/internal/data"
+)
+
+// Chain asks an ordered list of providers and merges their answers field by
+// field: the first non-empty value of each field wins and the name of the
+// provider it came from is recorded in SongDetail.Sources. A failing provider
+// is skipped; the chain only fails when no provider returned any details.
+// Details merged while some provider failed for a reason other than "not
+// found" are marked Partial.
+type Chain struct {
+	providers []SongDetailProvider
+}
+
+func NewChain(providers ...SongDetailProvider) *Chain {
+	return &Chain{providers: providers}
+}
+
+func (c *Chain) Name() string {
+	names := make([]string, 0, len(c.providers))
+	for _, p := range c.providers {
+		names = append(names, p.Name())
+	}
+
+	return strings.Join(names, ",")
+}
+
+func (c *Chain) Fetch(ctx context.Context, song, group string) (*data.SongDetail, error) {
+	merged := &data.SongDetail{Sources: make(data.DetailSources)}
+	found := false
+
+	var errs []error
+
+	for _, p := range c.providers {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+
+		detail, err := p.Fetch(ctx, song, group)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		found = true
+		merged.Merge(detail, p.Name())
+
+		if merged.Complete() {
+			break
+		}
+	}
+
+	// "Not found" is only the final answer when every provider gave it;
+	// other failures may go away on a retry.
+	var failures []error
+	for _, err := range errs {
+		if !errors.Is(err, ErrNotFound) {
+			failures = append(failures, err)
+		}
+	}
+
+	if found {
+		merged.Partial = len(failures) > 0
+		return merged, nil
+	}
+
+	if len(failures) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return nil, errors.Join(failures...)
+}
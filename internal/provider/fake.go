@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"test_task/internal/data"
+	"time"
+)
+
+// Fixture is a single canned provider answer.
+type Fixture struct {
+	Group string `json:"group"`
+	Song  string `json:"song"`
+	data.SongDetail
+}
+
+// FakeProvider serves song details from an in-memory set of fixtures and
+// never touches the network. Songs without a fixture yield ErrNotFound.
+type FakeProvider struct {
+	details map[string]data.SongDetail
+}
+
+func NewFake(fixtures ...Fixture) *FakeProvider {
+	p := &FakeProvider{details: make(map[string]data.SongDetail)}
+
+	for _, f := range fixtures {
+		p.details[detailKey(f.Song, f.Group)] = f.SongDetail
+	}
+
+	return p
+}
+
+// LoadFixtures reads a JSON array of fixtures from path.
+func LoadFixtures(path string) (*FakeProvider, error) {
+	js, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixtures []Fixture
+	err = json.Unmarshal(js, &fixtures)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFake(fixtures...), nil
+}
+
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *FakeProvider) Fetch(ctx context.Context, song, group string) (*data.SongDetail, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	detail, ok := p.details[detailKey(song, group)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	payload, err := json.Marshal(detail)
+	if err != nil {
+		return nil, err
+	}
+
+	detail.Provenance = []data.Provenance{{
+		Provider:   p.Name(),
+		FetchedAt:  time.Now(),
+		HTTPStatus: http.StatusOK,
+		Payload:    payload,
+	}}
+
+	return &detail, nil
+}
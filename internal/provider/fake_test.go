@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"test_task/internal/data"
+	"testing"
+)
+
+func TestFakeProvider(t *testing.T) {
+	p := NewFake(Fixture{
+		Group:      "Muse",
+		Song:       "Supermassive Black Hole",
+		SongDetail: data.SongDetail{Release: "16.07.2006", Text: "Ooh baby"},
+	})
+
+	detail, err := p.Fetch(context.Background(), "Supermassive Black Hole", "Muse")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if detail.Release != "16.07.2006" || detail.Text != "Ooh baby" {
+		t.Errorf("detail = %+v", detail)
+	}
+	if len(detail.Provenance) != 1 || detail.Provenance[0].Provider != "fake" {
+		t.Errorf("provenance = %+v", detail.Provenance)
+	}
+
+	_, err = p.Fetch(context.Background(), "Uprising", "Muse")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Fetch of a song without a fixture: got %v, want ErrNotFound", err)
+	}
+}
+
+func TestLoadFixtures(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixtures.json")
+
+	err := os.WriteFile(path, []byte(`[{"group": "Muse", "song": "Uprising", "releaseDate": "07.09.2009", "link": "https://example.com"}]`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := LoadFixtures(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	detail, err := p.Fetch(context.Background(), "Uprising", "Muse")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if detail.Release != "07.09.2009" || detail.Link != "https://example.com" {
+		t.Errorf("detail = %+v", detail)
+	}
+}
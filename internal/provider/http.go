@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"test_task/internal/data"
+	"time"
+)
+
+const maxPayloadBytes = 1_048_576
+
+type HTTPConfig struct {
+	Name    string
+	BaseURL string
+	Timeout time.Duration
+	Headers map[string]string
+}
+
+// HTTPProvider queries the GET {BaseURL}/info?group=...&song=... endpoint.
+type HTTPProvider struct {
+	name    string
+	infoURL string
+	headers http.Header
+	client  *http.Client
+}
+
+func NewHTTP(cfg HTTPConfig) (*HTTPProvider, error) {
+	u, err := url.Parse(cfg.BaseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid provider base url %q", cfg.BaseURL)
+	}
+
+	name := cfg.Name
+	if name == "" {
+		name = u.Host
+	}
+
+	headers := make(http.Header)
+	for key, value := range cfg.Headers {
+		headers.Set(key, value)
+	}
+
+	return &HTTPProvider{
+		name:    name,
+		infoURL: strings.TrimRight(u.String(), "/") + "/info",
+		headers: headers,
+		client:  &http.Client{Timeout: cfg.Timeout},
+	}, nil
+}
+
+func (p *HTTPProvider) Name() string {
+	return p.name
+}
+
+func (p *HTTPProvider) Fetch(ctx context.Context, song, group string) (*data.SongDetail, error) {
+	qs := url.Values{}
+	qs.Set("group", group)
+	qs.Set("song", song)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.infoURL+"?"+qs.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range p.headers {
+		req.Header[key] = values
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make API request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, &StatusError{Provider: p.name, StatusCode: resp.StatusCode}
+	}
+
+	payload, err := io.ReadAll(io.LimitReader(resp.Body, maxPayloadBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API response: %w", err)
+	}
+
+	var detail data.SongDetail
+	err = json.Unmarshal(payload, &detail)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse API response: %w", err)
+	}
+
+	detail.Provenance = []data.Provenance{{
+		Provider:   p.name,
+		FetchedAt:  time.Now(),
+		HTTPStatus: resp.StatusCode,
+		Payload:    payload,
+	}}
+
+	return &detail, nil
+}
+
+// ParseHeaders parses a "Key: Value, Key2: Value2" list into a header map.
+func ParseHeaders(s string) (map[string]string, error) {
+	headers := make(map[string]string)
+
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(pair, ":")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, errors.New("headers must be in the form \"Key: Value\"")
+		}
+
+		headers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	return headers, nil
+}
@@ -0,0 +1,167 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPRejectsInvalidBaseURL(t *testing.T) {
+	for _, baseURL := range []string{"", "localhost:8080", "/api", "http://"} {
+		_, err := NewHTTP(HTTPConfig{BaseURL: baseURL})
+		if err == nil {
+			t.Errorf("NewHTTP(%q) succeeded, want an error", baseURL)
+		}
+	}
+}
+
+func TestNewHTTPName(t *testing.T) {
+	tests := []struct {
+		cfg  HTTPConfig
+		want string
+	}{
+		{HTTPConfig{BaseURL: "http://meta.example.com:8080/api"}, "meta.example.com:8080"},
+		{HTTPConfig{Name: "primary", BaseURL: "http://meta.example.com"}, "primary"},
+	}
+
+	for _, tt := range tests {
+		p, err := NewHTTP(tt.cfg)
+		if err != nil {
+			t.Fatalf("NewHTTP(%+v): %v", tt.cfg, err)
+		}
+
+		if got := p.Name(); got != tt.want {
+			t.Errorf("NewHTTP(%+v).Name() = %q, want %q", tt.cfg, got, tt.want)
+		}
+	}
+}
+
+func TestHTTPProviderFetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/info" {
+			t.Errorf("path = %q, want /api/info", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("group"); got != "Muse" {
+			t.Errorf("group = %q, want Muse", got)
+		}
+		if got := r.URL.Query().Get("song"); got != "Supermassive Black Hole" {
+			t.Errorf("song = %q, want Supermassive Black Hole", got)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q, want Bearer secret", got)
+		}
+		if got := r.Header.Get("X-Client"); got != "music-library" {
+			t.Errorf("X-Client = %q, want music-library", got)
+		}
+
+		w.Write([]byte(`{"releaseDate": "16.07.2006", "text": "Ooh baby", "link": "https://www.youtube.com/watch?v=Xsp3_a-PMTw"}`))
+	}))
+	defer srv.Close()
+
+	p, err := NewHTTP(HTTPConfig{
+		Name:    "test",
+		BaseURL: srv.URL + "/api/",
+		Timeout: time.Second,
+		Headers: map[string]string{"Authorization": "Bearer secret", "x-client": "music-library"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	detail, err := p.Fetch(context.Background(), "Supermassive Black Hole", "Muse")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if detail.Release != "16.07.2006" || detail.Text != "Ooh baby" || detail.Link != "https://www.youtube.com/watch?v=Xsp3_a-PMTw" {
+		t.Errorf("detail = %+v", detail)
+	}
+
+	if len(detail.Provenance) != 1 {
+		t.Fatalf("got %d provenance entries, want 1", len(detail.Provenance))
+	}
+	if got := detail.Provenance[0]; got.Provider != "test" || got.HTTPStatus != http.StatusOK {
+		t.Errorf("provenance = %+v", got)
+	}
+}
+
+func TestHTTPProviderStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		check  func(error) bool
+	}{
+		{http.StatusNotFound, func(err error) bool { return errors.Is(err, ErrNotFound) }},
+		{http.StatusInternalServerError, func(err error) bool {
+			var statusErr *StatusError
+			return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusInternalServerError
+		}},
+	}
+
+	for _, tt := range tests {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+		}))
+
+		p, err := NewHTTP(HTTPConfig{BaseURL: srv.URL, Timeout: time.Second})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = p.Fetch(context.Background(), "Song", "Group")
+		if !tt.check(err) {
+			t.Errorf("status %d: unexpected error %v", tt.status, err)
+		}
+
+		srv.Close()
+	}
+}
+
+func TestHTTPProviderTimeout(t *testing.T) {
+	release := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	p, err := NewHTTP(HTTPConfig{BaseURL: srv.URL, Timeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	started := time.Now()
+
+	_, err = p.Fetch(context.Background(), "Song", "Group")
+	if err == nil {
+		t.Fatal("Fetch succeeded, want a timeout")
+	}
+
+	if took := time.Since(started); took > time.Second {
+		t.Errorf("Fetch took %v, want it to give up after the 50ms timeout", took)
+	}
+}
+
+func TestParseHeaders(t *testing.T) {
+	headers, err := ParseHeaders(" Authorization: Bearer a:b , X-Client: music-library,")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(headers) != 2 || headers["Authorization"] != "Bearer a:b" || headers["X-Client"] != "music-library" {
+		t.Errorf("headers = %v", headers)
+	}
+
+	for _, s := range []string{"Authorization", ": value"} {
+		_, err := ParseHeaders(s)
+		if err == nil {
+			t.Errorf("ParseHeaders(%q) succeeded, want an error", s)
+		}
+	}
+}
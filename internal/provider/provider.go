@@ -0,0 +1,29 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"test_task/internal/data"
+)
+
+var (
+	ErrNotFound = errors.New("song details not found")
+)
+
+// SongDetailProvider fetches release date, lyrics and link of a song from an
+// external metadata source.
+type SongDetailProvider interface {
+	Name() string
+	Fetch(ctx context.Context, song, group string) (*data.SongDetail, error)
+}
+
+// StatusError is returned when a provider answers with an unexpected HTTP status.
+type StatusError struct {
+	Provider   string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: unexpected status code: %d", e.Provider, e.StatusCode)
+}
@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"test_task/internal/data"
+	"time"
+)
+
+var ErrThrottled = errors.New("provider throttled: the request deadline would pass before the rate limit allows the call")
+
+type RateLimitConfig struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimiter is a token bucket in front of a provider. Callers wait for a
+// token; if the wait would outlast their context deadline they get
+// ErrThrottled straight away instead of waiting in vain.
+type RateLimiter struct {
+	next SongDetailProvider
+	cfg  RateLimitConfig
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func NewRateLimiter(p SongDetailProvider, cfg RateLimitConfig) *RateLimiter {
+	if cfg.Burst < 1 {
+		cfg.Burst = 1
+	}
+
+	return &RateLimiter{
+		next:   p,
+		cfg:    cfg,
+		tokens: float64(cfg.Burst),
+		last:   time.Now(),
+	}
+}
+
+func (l *RateLimiter) Name() string {
+	return l.next.Name()
+}
+
+func (l *RateLimiter) Fetch(ctx context.Context, song, group string) (*data.SongDetail, error) {
+	err := l.wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.next.Fetch(ctx, song, group)
+}
+
+func (l *RateLimiter) wait(ctx context.Context) error {
+	if l.cfg.Rate <= 0 {
+		return nil
+	}
+
+	delay, err := l.reserve(ctx)
+	if err != nil || delay <= 0 {
+		return err
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.release()
+		return ctx.Err()
+	}
+}
+
+// reserve takes a token, possibly from the future, and returns how long the
+// caller has to wait until that token is actually available.
+func (l *RateLimiter) reserve(ctx context.Context) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.cfg.Rate
+	if l.tokens > float64(l.cfg.Burst) {
+		l.tokens = float64(l.cfg.Burst)
+	}
+	l.last = now
+
+	var delay time.Duration
+	if l.tokens < 1 {
+		delay = time.Duration((1 - l.tokens) / l.cfg.Rate * float64(time.Second))
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && now.Add(delay).After(deadline) {
+		return 0, ErrThrottled
+	}
+
+	l.tokens--
+
+	return delay, nil
+}
+
+func (l *RateLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens++
+}
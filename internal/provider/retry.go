@@ -0,0 +1,88 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"test_task/internal/data"
+	"time"
+)
+
+type RetryConfig struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+type retryProvider struct {
+	next SongDetailProvider
+	cfg  RetryConfig
+}
+
+// WithRetry retries transient failures of p (5xx answers and timeouts) with
+// exponential backoff and jitter.
+func WithRetry(p SongDetailProvider, cfg RetryConfig) SongDetailProvider {
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+
+	return &retryProvider{next: p, cfg: cfg}
+}
+
+func (p *retryProvider) Name() string {
+	return p.next.Name()
+}
+
+func (p *retryProvider) Fetch(ctx context.Context, song, group string) (*data.SongDetail, error) {
+	for attempt := 1; ; attempt++ {
+		detail, err := p.next.Fetch(ctx, song, group)
+		if err == nil {
+			return detail, nil
+		}
+
+		if attempt >= p.cfg.MaxAttempts || ctx.Err() != nil || !isTransient(err) {
+			return nil, err
+		}
+
+		timer := time.NewTimer(p.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the delay before the next attempt: the exponential delay
+// capped at MaxDelay, half of which is randomised.
+func (p *retryProvider) backoff(attempt int) time.Duration {
+	d := p.cfg.BaseDelay << (attempt - 1)
+	if d <= 0 || (p.cfg.MaxDelay > 0 && d > p.cfg.MaxDelay) {
+		d = p.cfg.MaxDelay
+	}
+
+	if d <= 0 {
+		return 0
+	}
+
+	half := d / 2
+	return half + rand.N(half+1)
+}
+
+// isTransient reports whether err is worth another attempt.
+func isTransient(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,4 @@
+ALTER TABLE songs
+    DROP CONSTRAINT IF EXISTS songs_enrichment_status_check,
+    DROP COLUMN IF EXISTS enrichment_error,
+    DROP COLUMN IF EXISTS enrichment_status;
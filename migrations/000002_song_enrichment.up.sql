@@ -0,0 +1,7 @@
+ALTER TABLE songs
+    ADD COLUMN enrichment_status VARCHAR(16) NOT NULL DEFAULT 'done',
+    ADD COLUMN enrichment_error TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE songs
+    ALTER COLUMN enrichment_status SET DEFAULT 'pending',
+    ADD CONSTRAINT songs_enrichment_status_check CHECK (enrichment_status IN ('pending', 'done', 'failed'));
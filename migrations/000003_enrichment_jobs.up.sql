@@ -0,0 +1,17 @@
+CREATE TABLE enrichment_jobs (
+                                 id BIGSERIAL PRIMARY KEY,
+                                 song_id BIGINT NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+                                 status VARCHAR(16) NOT NULL DEFAULT 'pending',
+                                 attempts INTEGER NOT NULL DEFAULT 0,
+                                 next_run_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW(),
+                                 locked_until TIMESTAMP(0) WITH TIME ZONE,
+                                 last_error TEXT NOT NULL DEFAULT '',
+                                 created_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW(),
+                                 updated_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW(),
+                                 CONSTRAINT enrichment_jobs_status_check CHECK (status IN ('pending', 'done', 'failed'))
+);
+
+CREATE INDEX enrichment_jobs_due_idx ON enrichment_jobs (next_run_at) WHERE status = 'pending';
+
+INSERT INTO enrichment_jobs (song_id)
+SELECT id FROM songs WHERE enrichment_status = 'pending';
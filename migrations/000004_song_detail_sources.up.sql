@@ -0,0 +1,2 @@
+ALTER TABLE songs
+    ADD COLUMN detail_sources JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,10 @@
+CREATE TABLE song_provenance (
+                                 id BIGSERIAL PRIMARY KEY,
+                                 song_id BIGINT NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+                                 provider VARCHAR(255) NOT NULL,
+                                 fetched_at TIMESTAMP(0) WITH TIME ZONE NOT NULL,
+                                 http_status INTEGER NOT NULL,
+                                 payload JSONB NOT NULL
+);
+
+CREATE INDEX song_provenance_song_id_idx ON song_provenance (song_id);
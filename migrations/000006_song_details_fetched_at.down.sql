@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS songs_details_fetched_at_idx;
+
+ALTER TABLE songs
+    DROP COLUMN IF EXISTS details_fetched_at;
@@ -0,0 +1,6 @@
+ALTER TABLE songs
+    ADD COLUMN details_fetched_at TIMESTAMP(0) WITH TIME ZONE;
+
+UPDATE songs SET details_fetched_at = updated_at WHERE enrichment_status = 'done';
+
+CREATE INDEX songs_details_fetched_at_idx ON songs (details_fetched_at) WHERE enrichment_status = 'done';
@@ -0,0 +1,17 @@
+DROP INDEX IF EXISTS unique_song_group;
+
+ALTER TABLE songs
+    ADD COLUMN group_name VARCHAR(255);
+
+UPDATE songs
+SET group_name = g.name
+FROM groups g
+WHERE g.id = songs.group_id;
+
+ALTER TABLE songs
+    ALTER COLUMN group_name SET NOT NULL,
+    DROP COLUMN group_id;
+
+CREATE UNIQUE INDEX unique_song_group ON songs (song_name, group_name);
+
+DROP TABLE IF EXISTS groups;
@@ -0,0 +1,54 @@
+CREATE TABLE groups (
+                        id BIGSERIAL PRIMARY KEY,
+                        name VARCHAR(255) NOT NULL,
+                        created_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW(),
+                        updated_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX unique_group_name ON groups (lower(name));
+
+INSERT INTO groups (name)
+SELECT DISTINCT ON (lower(n)) n
+FROM (SELECT btrim(regexp_replace(group_name, '\s+', ' ', 'g')) AS n FROM songs) s
+ORDER BY lower(n), n;
+
+ALTER TABLE songs
+    ADD COLUMN group_id BIGINT REFERENCES groups (id) ON DELETE RESTRICT;
+
+UPDATE songs
+SET group_id = g.id
+FROM groups g
+WHERE lower(g.name) = lower(btrim(regexp_replace(songs.group_name, '\s+', ' ', 'g')));
+
+-- Spellings like "Muse" and "muse " now point to the same group, so a song
+-- that existed under several of them would be duplicated. Those copies have
+-- to be merged or renamed by hand before the migration can run.
+--
+-- Earlier revisions of this migration dropped the newer copies instead. The
+-- schema they leave behind is the same, so databases migrated with them need
+-- no follow-up migration; the check has to stay here because it must run
+-- before the songs are moved onto the normalised groups.
+DO $$
+DECLARE
+    conflicts TEXT;
+BEGIN
+    SELECT string_agg(format('%s - %s (ids %s)', g.name, d.song_name, d.ids), '; ')
+    INTO conflicts
+    FROM (SELECT group_id, song_name, string_agg(id::text, ', ' ORDER BY id) AS ids
+          FROM songs
+          GROUP BY group_id, song_name
+          HAVING count(*) > 1) d
+    JOIN groups g ON g.id = d.group_id;
+
+    IF conflicts IS NOT NULL THEN
+        RAISE EXCEPTION 'songs would be duplicated once group names are normalised, merge or rename them first: %', conflicts;
+    END IF;
+END $$;
+
+DROP INDEX IF EXISTS unique_song_group;
+
+ALTER TABLE songs
+    ALTER COLUMN group_id SET NOT NULL,
+    DROP COLUMN group_name;
+
+CREATE UNIQUE INDEX unique_song_group ON songs (song_name, group_id);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS unique_album_track;
+
+ALTER TABLE songs
+    DROP CONSTRAINT IF EXISTS songs_album_track_check,
+    DROP CONSTRAINT IF EXISTS songs_track_number_check,
+    DROP COLUMN IF EXISTS track_number,
+    DROP COLUMN IF EXISTS album_id;
+
+DROP TABLE IF EXISTS albums;
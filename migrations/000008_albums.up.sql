@@ -0,0 +1,18 @@
+CREATE TABLE albums (
+                        id BIGSERIAL PRIMARY KEY,
+                        title VARCHAR(255) NOT NULL,
+                        group_id BIGINT NOT NULL REFERENCES groups (id) ON DELETE RESTRICT,
+                        release_date DATE,
+                        created_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW(),
+                        updated_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX unique_album_group ON albums (lower(title), group_id);
+
+ALTER TABLE songs
+    ADD COLUMN album_id BIGINT REFERENCES albums (id) ON DELETE RESTRICT,
+    ADD COLUMN track_number INTEGER,
+    ADD CONSTRAINT songs_track_number_check CHECK (track_number > 0),
+    ADD CONSTRAINT songs_album_track_check CHECK ((album_id IS NULL) = (track_number IS NULL));
+
+CREATE UNIQUE INDEX unique_album_track ON songs (album_id, track_number);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS songs_release_date_idx;
+
+ALTER TABLE songs
+    DROP COLUMN IF EXISTS release_date;
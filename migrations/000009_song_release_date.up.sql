@@ -0,0 +1,20 @@
+ALTER TABLE songs
+    ADD COLUMN release_date DATE;
+
+CREATE FUNCTION pg_temp.parse_release(s TEXT) RETURNS DATE AS $$
+BEGIN
+    IF s ~ '^\d{1,2}\.\d{1,2}\.\d{4}$' THEN
+        RETURN to_date(s, 'DD.MM.YYYY');
+    END IF;
+    IF s ~ '^\d{4}-\d{2}-\d{2}$' THEN
+        RETURN s::DATE;
+    END IF;
+    RETURN NULL;
+EXCEPTION WHEN others THEN
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+UPDATE songs SET release_date = pg_temp.parse_release(btrim(release));
+
+CREATE INDEX songs_release_date_idx ON songs (release_date);
@@ -0,0 +1,2 @@
+ALTER TABLE songs
+    ALTER COLUMN text TYPE TEXT;
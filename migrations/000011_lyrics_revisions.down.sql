@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS lyrics_revisions;
+DROP FUNCTION IF EXISTS lyrics_revisions_append_only();
@@ -0,0 +1,25 @@
+CREATE TABLE lyrics_revisions (
+                                  id BIGSERIAL PRIMARY KEY,
+                                  song_id BIGINT NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+                                  revision INTEGER NOT NULL,
+                                  author VARCHAR(255) NOT NULL,
+                                  text_hash CHAR(64) NOT NULL,
+                                  text TEXT NOT NULL,
+                                  created_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW(),
+                                  CONSTRAINT unique_lyrics_revision UNIQUE (song_id, revision)
+);
+
+CREATE FUNCTION lyrics_revisions_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'lyrics revisions are append-only';
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER lyrics_revisions_no_update
+    BEFORE UPDATE ON lyrics_revisions
+    FOR EACH ROW EXECUTE FUNCTION lyrics_revisions_append_only();
+
+INSERT INTO lyrics_revisions (song_id, revision, author, text_hash, text)
+SELECT id, 1, 'system', encode(sha256(convert_to(text, 'UTF8')), 'hex'), text
+FROM songs
+WHERE text <> '';
@@ -0,0 +1,27 @@
+-- Without deleted_at, songs in the trash would come back as live songs and
+-- may clash with the songs that took their place. Rolling back must not lose
+-- them either, so the trash has to be restored or purged by hand first.
+DO $$
+DECLARE
+    trashed TEXT;
+BEGIN
+    SELECT string_agg(id::text, ', ' ORDER BY id)
+    INTO trashed
+    FROM songs
+    WHERE deleted_at IS NOT NULL;
+
+    IF trashed IS NOT NULL THEN
+        RAISE EXCEPTION 'songs are still in the trash, restore or purge them first: ids %', trashed;
+    END IF;
+END $$;
+
+DROP INDEX IF EXISTS songs_deleted_at_idx;
+
+DROP INDEX IF EXISTS unique_album_track;
+CREATE UNIQUE INDEX unique_album_track ON songs (album_id, track_number);
+
+DROP INDEX IF EXISTS unique_song_group;
+CREATE UNIQUE INDEX unique_song_group ON songs (song_name, group_id);
+
+ALTER TABLE songs
+    DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,12 @@
+ALTER TABLE songs
+    ADD COLUMN deleted_at TIMESTAMP(0) WITH TIME ZONE;
+
+-- A song in the trash no longer blocks adding it again or reusing its track
+-- number; restoring it fails while its place is taken.
+DROP INDEX IF EXISTS unique_song_group;
+CREATE UNIQUE INDEX unique_song_group ON songs (song_name, group_id) WHERE deleted_at IS NULL;
+
+DROP INDEX IF EXISTS unique_album_track;
+CREATE UNIQUE INDEX unique_album_track ON songs (album_id, track_number) WHERE deleted_at IS NULL;
+
+CREATE INDEX songs_deleted_at_idx ON songs (deleted_at) WHERE deleted_at IS NOT NULL;
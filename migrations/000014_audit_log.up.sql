@@ -0,0 +1,15 @@
+-- song_id deliberately has no foreign key, so the history of a song outlives
+-- the song itself.
+CREATE TABLE audit_log (
+                           id BIGSERIAL PRIMARY KEY,
+                           song_id BIGINT NOT NULL,
+                           action VARCHAR(16) NOT NULL,
+                           actor VARCHAR(255) NOT NULL,
+                           request_id VARCHAR(64) NOT NULL DEFAULT '',
+                           changes JSONB NOT NULL DEFAULT '{}',
+                           created_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW(),
+                           CONSTRAINT audit_log_action_check CHECK (action IN ('insert', 'update', 'delete', 'restore', 'purge'))
+);
+
+CREATE INDEX audit_log_song_idx ON audit_log (song_id, id);
+CREATE INDEX audit_log_created_at_idx ON audit_log (created_at);
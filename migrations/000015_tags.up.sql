@@ -0,0 +1,18 @@
+CREATE TABLE tags (
+                      id BIGSERIAL PRIMARY KEY,
+                      kind VARCHAR(8) NOT NULL,
+                      name VARCHAR(64) NOT NULL,
+                      created_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW(),
+                      CONSTRAINT tags_kind_check CHECK (kind IN ('tag', 'genre'))
+);
+
+CREATE UNIQUE INDEX unique_tag_name ON tags (kind, lower(name));
+
+CREATE TABLE song_tags (
+                           song_id BIGINT NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+                           tag_id BIGINT NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+                           created_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW(),
+                           PRIMARY KEY (song_id, tag_id)
+);
+
+CREATE INDEX song_tags_tag_idx ON song_tags (tag_id);
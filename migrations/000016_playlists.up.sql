@@ -0,0 +1,21 @@
+CREATE TABLE playlists (
+                           id BIGSERIAL PRIMARY KEY,
+                           name VARCHAR(255) NOT NULL,
+                           description TEXT NOT NULL DEFAULT '',
+                           created_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW(),
+                           updated_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Positions are shifted in bulk when items are inserted, moved or removed, so
+-- their uniqueness is only checked at commit.
+CREATE TABLE playlist_items (
+                                id BIGSERIAL PRIMARY KEY,
+                                playlist_id BIGINT NOT NULL REFERENCES playlists (id) ON DELETE CASCADE,
+                                song_id BIGINT NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+                                position INTEGER NOT NULL,
+                                added_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW(),
+                                CONSTRAINT playlist_items_position_check CHECK (position > 0),
+                                CONSTRAINT unique_playlist_position UNIQUE (playlist_id, position) DEFERRABLE INITIALLY DEFERRED
+);
+
+CREATE INDEX playlist_items_song_idx ON playlist_items (song_id);
@@ -0,0 +1,29 @@
+CREATE TABLE song_links (
+                            id BIGSERIAL PRIMARY KEY,
+                            song_id BIGINT NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+                            platform VARCHAR(16) NOT NULL,
+                            url TEXT NOT NULL,
+                            is_primary BOOLEAN NOT NULL DEFAULT false,
+                            created_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW(),
+                            updated_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW(),
+                            CONSTRAINT song_links_platform_check CHECK (platform IN ('youtube', 'spotify', 'apple_music', 'bandcamp')),
+                            CONSTRAINT unique_song_platform UNIQUE (song_id, platform)
+);
+
+CREATE UNIQUE INDEX unique_song_primary_link ON song_links (song_id) WHERE is_primary;
+CREATE INDEX song_links_platform_idx ON song_links (platform);
+
+-- Carry over the links we already have wherever the platform is recognisable.
+INSERT INTO song_links (song_id, platform, url, is_primary)
+SELECT id, platform, link, true
+FROM (
+    SELECT id, btrim(link) AS link,
+           CASE
+               WHEN link ~* '^https?://([a-z0-9-]+\.)*(youtube\.com|youtu\.be)(/|$)' THEN 'youtube'
+               WHEN link ~* '^https?://([a-z0-9-]+\.)*spotify\.com(/|$)' THEN 'spotify'
+               WHEN link ~* '^https?://([a-z0-9-]+\.)*(music|itunes)\.apple\.com(/|$)' THEN 'apple_music'
+               WHEN link ~* '^https?://([a-z0-9-]+\.)*bandcamp\.com(/|$)' THEN 'bandcamp'
+           END AS platform
+    FROM songs
+) s
+WHERE platform IS NOT NULL;
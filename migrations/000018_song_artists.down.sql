@@ -0,0 +1,28 @@
+-- Without the featured artists, collaborations may duplicate another song of
+-- the same group. Rolling back must not lose songs, so those have to be
+-- renamed or trashed by hand first.
+DO $$
+DECLARE
+    conflicts TEXT;
+BEGIN
+    SELECT string_agg(format('%s - %s (ids %s)', g.name, d.song_name, d.ids), '; ')
+    INTO conflicts
+    FROM (SELECT group_id, song_name, string_agg(id::text, ', ' ORDER BY id) AS ids
+          FROM songs
+          WHERE deleted_at IS NULL
+          GROUP BY group_id, song_name
+          HAVING count(*) > 1) d
+    JOIN groups g ON g.id = d.group_id;
+
+    IF conflicts IS NOT NULL THEN
+        RAISE EXCEPTION 'songs would be duplicated without their featured artists, rename or trash them first: %', conflicts;
+    END IF;
+END $$;
+
+DROP INDEX IF EXISTS unique_song_group;
+CREATE UNIQUE INDEX unique_song_group ON songs (song_name, group_id) WHERE deleted_at IS NULL;
+
+ALTER TABLE songs
+    DROP COLUMN IF EXISTS credits_key;
+
+DROP TABLE IF EXISTS song_artists;
@@ -0,0 +1,18 @@
+CREATE TABLE song_artists (
+                              song_id BIGINT NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+                              group_id BIGINT NOT NULL REFERENCES groups (id) ON DELETE RESTRICT,
+                              role VARCHAR(16) NOT NULL,
+                              created_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW(),
+                              CONSTRAINT song_artists_role_check CHECK (role IN ('feat', 'producer', 'composer')),
+                              PRIMARY KEY (song_id, group_id, role)
+);
+
+CREATE INDEX song_artists_group_idx ON song_artists (group_id);
+
+-- credits_key lists the ids of the featured groups of a song in ascending
+-- order, so "Song" by A and "Song" by A feat. B are different songs.
+ALTER TABLE songs
+    ADD COLUMN credits_key TEXT NOT NULL DEFAULT '';
+
+DROP INDEX IF EXISTS unique_song_group;
+CREATE UNIQUE INDEX unique_song_group ON songs (song_name, group_id, credits_key) WHERE deleted_at IS NULL;
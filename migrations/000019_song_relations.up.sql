@@ -0,0 +1,12 @@
+CREATE TABLE song_relations (
+                                id BIGSERIAL PRIMARY KEY,
+                                song_id BIGINT NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+                                related_song_id BIGINT NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+                                type VARCHAR(16) NOT NULL,
+                                created_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW(),
+                                CONSTRAINT song_relations_type_check CHECK (type IN ('cover_of', 'remix_of', 'live_version_of', 'sample_of')),
+                                CONSTRAINT song_relations_self_check CHECK (song_id <> related_song_id),
+                                CONSTRAINT unique_song_relation UNIQUE (song_id, related_song_id, type)
+);
+
+CREATE INDEX song_relations_related_idx ON song_relations (related_song_id);
@@ -0,0 +1,11 @@
+CREATE TABLE lyric_translations (
+                                    id BIGSERIAL PRIMARY KEY,
+                                    song_id BIGINT NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+                                    language VARCHAR(35) NOT NULL,
+                                    text TEXT NOT NULL,
+                                    author VARCHAR(255) NOT NULL,
+                                    created_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW(),
+                                    updated_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX unique_song_language ON lyric_translations (song_id, lower(language));
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS lyric_timings;
@@ -0,0 +1,6 @@
+CREATE TABLE lyric_timings (
+                               song_id BIGINT NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+                               line INTEGER NOT NULL CHECK (line > 0),
+                               offset_ms BIGINT NOT NULL CHECK (offset_ms >= 0),
+                               PRIMARY KEY (song_id, line)
+);
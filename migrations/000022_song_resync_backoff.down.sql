@@ -0,0 +1,3 @@
+ALTER TABLE songs
+    DROP COLUMN IF EXISTS resync_retry_at,
+    DROP COLUMN IF EXISTS resync_failures;
@@ -0,0 +1,3 @@
+ALTER TABLE songs
+    ADD COLUMN resync_failures INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN resync_retry_at TIMESTAMP(0) WITH TIME ZONE;
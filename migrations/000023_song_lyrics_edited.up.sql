@@ -0,0 +1,8 @@
+-- Lyrics written by users, rather than fetched from the provider, are not
+-- overwritten when the details of the song are fetched again.
+ALTER TABLE songs
+    ADD COLUMN lyrics_edited_at TIMESTAMP(0) WITH TIME ZONE;
+
+UPDATE songs
+SET lyrics_edited_at = NOW()
+WHERE id IN (SELECT song_id FROM lyric_timings);
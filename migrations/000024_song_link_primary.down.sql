@@ -0,0 +1,4 @@
+UPDATE songs s
+SET link = l.url
+FROM song_links l
+WHERE l.song_id = s.id AND l.is_primary;
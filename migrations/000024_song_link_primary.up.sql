@@ -0,0 +1,5 @@
+-- The link of a song is now its primary platform link, so songs.link only
+-- keeps links whose platform isn't recognised.
+UPDATE songs s
+SET link = ''
+WHERE EXISTS (SELECT 1 FROM song_links l WHERE l.song_id = s.id AND l.url = btrim(s.link));